@@ -6,7 +6,6 @@ import (
 	"log"
 	"os"
 
-	"github.com/TheLeeeo/gql-test-suite/crawler"
 	crawlserver "github.com/TheLeeeo/gql-test-suite/crawler/server.go"
 	"github.com/TheLeeeo/gql-test-suite/introspection"
 	"github.com/spf13/cobra"
@@ -17,6 +16,8 @@ const (
 	keyHttpPort        = "http-port"
 	keyEnablePolling   = "enable-polling"
 	keyPollingInterval = "polling-interval"
+	keyAutoCrawl       = "auto-crawl"
+	keyWebhookUrl      = "webhook-url"
 )
 
 func init() {
@@ -30,6 +31,12 @@ func init() {
 
 	startCmd.Flags().Int(keyPollingInterval, 10, "The interval in minutes to poll for changes to the target graphql schema")
 	viper.BindPFlag(keyPollingInterval, startCmd.Flags().Lookup(keyPollingInterval))
+
+	startCmd.Flags().Bool(keyAutoCrawl, false, "Crawl the added and changed operations when polling finds changes to the schema")
+	viper.BindPFlag(keyAutoCrawl, startCmd.Flags().Lookup(keyAutoCrawl))
+
+	startCmd.Flags().String(keyWebhookUrl, "", "An url to post a summary of every automatic crawl to")
+	viper.BindPFlag(keyWebhookUrl, startCmd.Flags().Lookup(keyWebhookUrl))
 }
 
 var serverCmd = &cobra.Command{
@@ -42,19 +49,15 @@ var startCmd = &cobra.Command{
 	Short: "Start the crawl server",
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := crawlserver.Config{
-			HttpPort: viper.GetString(keyHttpPort),
-
-			CrawlerConfig: crawler.Config{
-				ClientConfig: introspection.Config{
-					TargetUrl: viper.GetString(keyTarget),
-					Headers:   parseHeaders(viper.GetStringSlice(keyHeaders)),
-					PollingConfig: introspection.PollingConfig{
-						Enabled:  viper.GetBool(keyEnablePolling),
-						Interval: viper.GetInt(keyPollingInterval),
-					},
-				},
-				Ignore: viper.GetStringSlice(keyIgnore),
-			},
+			HttpPort:      viper.GetString(keyHttpPort),
+			CrawlerConfig: newCrawlerConfig(),
+			AutoCrawl:     viper.GetBool(keyAutoCrawl),
+			WebhookUrl:    viper.GetString(keyWebhookUrl),
+		}
+
+		cfg.CrawlerConfig.ClientConfig.PollingConfig = introspection.PollingConfig{
+			Enabled:  viper.GetBool(keyEnablePolling),
+			Interval: viper.GetInt(keyPollingInterval),
 		}
 
 		err := validateConfig(&cfg)
@@ -67,6 +70,7 @@ var startCmd = &cobra.Command{
 
 		s := crawlserver.New(cfg)
 		err = s.Run()
+		cfg.CrawlerConfig.Recorder.Close()
 		if err != nil {
 			log.Println("error running server: ", err)
 			os.Exit(1)
@@ -83,5 +87,17 @@ func validateConfig(cfg *crawlserver.Config) error {
 		return errors.New("polling interval must be greater than 0")
 	}
 
+	if cfg.AutoCrawl && !cfg.CrawlerConfig.ClientConfig.PollingConfig.Enabled {
+		return errors.New("auto-crawl requires polling to be enabled")
+	}
+
+	if cfg.WebhookUrl != "" && !cfg.AutoCrawl {
+		return errors.New("webhook-url requires auto-crawl to be enabled")
+	}
+
+	if cfg.CrawlerConfig.RateLimit < 0 {
+		return errors.New("rate limit can not be negative")
+	}
+
 	return nil
 }
@@ -2,21 +2,89 @@ package crawlcli
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/TheLeeeo/gql-test-suite/client"
 	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
+	"github.com/TheLeeeo/gql-test-suite/crawler/report"
+	"github.com/TheLeeeo/gql-test-suite/crawler/selector"
 	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema/manager"
+	"github.com/TheLeeeo/gql-test-suite/utils"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 const (
-	keyTarget  = "target-url"
-	keyIgnore  = "ignore"
-	keyHeaders = "headers"
-	keyVerbose = "verbose"
+	keyTarget     = "target-url"
+	keyIgnore     = "ignore"
+	keySchema     = "schema"
+	keyInclude    = "include"
+	keyHeaders    = "headers"
+	keyIdentity   = "identity"
+	keyIdentities = "identities"
+	keyVerbose    = "verbose"
+	keyMatrix     = "matrix"
+	keyProbe      = "probe-fields"
+	keyBaseline   = "baseline"
+	keySaveBase   = "save-baseline"
+	keyFormat     = "format"
+	keyOutput     = "output"
+	keyFailOn     = "fail-on"
+	keyExpect     = "expect"
+	keyExpectPub  = "expect-public"
+	keyExpects    = "expectations"
+	keyWorkers    = "concurrency"
+	keyBatchSize  = "batch-size"
+	keyRateLimit  = "rate-limit"
+	keyOptional   = "optional-args"
+	keyScalars    = "scalars-file"
+	keyRules      = "rules"
+	keyMaxDepth   = "max-depth"
+	keyNullable   = "include-nullable"
+	keyMaxFields  = "max-fields"
+
+	keyMutations   = "mutations"
+	keyConfirm     = "confirm-mutations"
+	keyDestructive = "destructive-patterns"
+	keyProduction  = "production-hosts"
+
+	keyRecover  = "recover-schema"
+	keyWordlist = "wordlist"
+
+	keyRecord = "record"
+	keyReplay = "replay"
+
+	keyTimeout        = "request-timeout"
+	keyFuzz           = "fuzz"
+	keyFuzzSeed       = "fuzz-seed"
+	keyFuzzIterations = "fuzz-iterations"
+	keyFuzzMutations  = "fuzz-mutations"
+
+	keyLimits        = "limits"
+	keyLimitsCeiling = "limits-ceiling"
+	keyLimit         = "limit"
+
+	keySubscriptions       = "subscriptions"
+	keySubscriptionUrl     = "subscription-url"
+	keySubscriptionTimeout = "subscription-timeout"
+)
+
+// The exit codes of a crawl run
+const (
+	// The crawl completed but the results violate the failure policy, regressed from the baseline or exceeded an expected limit
+	exitFindings = 1
+	// The crawl could not be completed, or every request of it failed
+	exitCrawlError = 2
+	// The crawl could not be started due to invalid flags or configuration
+	exitConfigError = 3
 )
 
 func init() {
@@ -27,12 +95,132 @@ func init() {
 	CrawlCmd.PersistentFlags().StringP(keyTarget, "t", "", "The graphql endpoint to crawl")
 	viper.BindPFlag(keyTarget, CrawlCmd.PersistentFlags().Lookup(keyTarget))
 
-	CrawlCmd.PersistentFlags().StringSliceP(keyIgnore, "i", []string{}, "Queries and mutations to ignore")
+	CrawlCmd.PersistentFlags().String(keySchema, "", "Load the schema from a saved introspection result (.json), an SDL file or a directory of SDL files instead of introspecting the target")
+	viper.BindPFlag(keySchema, CrawlCmd.PersistentFlags().Lookup(keySchema))
+
+	CrawlCmd.PersistentFlags().StringArrayP(keyIgnore, "i", []string{}, "Selectors of the operations to ignore, eg. \"admin*\", \"/^internal/\", \"kind:mutation&returns:User\", \"arg:*Input\" or \"deprecated\". A \"&\" within a term is escaped as \"\\&\". Can be repeated")
 	viper.BindPFlag(keyIgnore, CrawlCmd.PersistentFlags().Lookup(keyIgnore))
 
+	CrawlCmd.PersistentFlags().StringArray(keyInclude, []string{}, "Selectors of the operations to crawl, every operation is crawled if not set. Uses the same syntax as --ignore")
+	viper.BindPFlag(keyInclude, CrawlCmd.PersistentFlags().Lookup(keyInclude))
+
 	CrawlCmd.PersistentFlags().StringSliceP(keyHeaders, "H", []string{}, "Headers to send with the request, formatted like \"k1:v1,k2,v2\"")
 	viper.BindPFlag(keyHeaders, CrawlCmd.PersistentFlags().Lookup(keyHeaders))
 
+	CrawlCmd.PersistentFlags().StringArray(keyIdentity, []string{}, "An identity to perform every operation as, formatted like \"name=k1:v1;k2:v2\". The headers of --headers are sent as well, unless the identity has no headers of its own. Can be repeated")
+	viper.BindPFlag(keyIdentity, CrawlCmd.PersistentFlags().Lookup(keyIdentity))
+
+	CrawlCmd.PersistentFlags().StringArray(keyExpect, []string{}, fmt.Sprintf("Who an operation is supposed to be allowed for, formatted like \"name=expectation\" or \"mutation.name=expectation\" where the expectation is one of %v. Can be repeated", crawler.Expectations))
+	viper.BindPFlag(keyExpect, CrawlCmd.PersistentFlags().Lookup(keyExpect))
+
+	CrawlCmd.PersistentFlags().StringSlice(keyExpectPub, []string{}, "Operations expected to be allowed for everyone, short for --expect name=public")
+	viper.BindPFlag(keyExpectPub, CrawlCmd.PersistentFlags().Lookup(keyExpectPub))
+
+	CrawlCmd.PersistentFlags().Int(keyWorkers, 1, "The number of operations to perform at the same time")
+	viper.BindPFlag(keyWorkers, CrawlCmd.PersistentFlags().Lookup(keyWorkers))
+
+	CrawlCmd.PersistentFlags().Int(keyBatchSize, 1, "The number of queries to send per array batched request, to crawl faster or to test authorization in batches")
+	viper.BindPFlag(keyBatchSize, CrawlCmd.PersistentFlags().Lookup(keyBatchSize))
+
+	CrawlCmd.PersistentFlags().Int(keyTimeout, 30, "The number of seconds to wait for the response of a request, 0 means no limit")
+	viper.BindPFlag(keyTimeout, CrawlCmd.PersistentFlags().Lookup(keyTimeout))
+
+	CrawlCmd.PersistentFlags().Float64(keyRateLimit, 0, "The maximum number of requests to send per second, 0 means no limit")
+	viper.BindPFlag(keyRateLimit, CrawlCmd.PersistentFlags().Lookup(keyRateLimit))
+
+	CrawlCmd.PersistentFlags().Bool(keyOptional, false, "Also send generated values for optional arguments")
+	viper.BindPFlag(keyOptional, CrawlCmd.PersistentFlags().Lookup(keyOptional))
+
+	CrawlCmd.PersistentFlags().Int(keyMaxDepth, 0, fmt.Sprintf("The maximum depth of nested selections in generated operations, 0 means %d", manager.DefaultMaxDepth))
+	viper.BindPFlag(keyMaxDepth, CrawlCmd.PersistentFlags().Lookup(keyMaxDepth))
+
+	CrawlCmd.PersistentFlags().Bool(keyNullable, false, "Also select nullable fields in generated operations")
+	viper.BindPFlag(keyNullable, CrawlCmd.PersistentFlags().Lookup(keyNullable))
+
+	CrawlCmd.PersistentFlags().Int(keyMaxFields, 0, "The maximum number of fields to select per type, 0 means no limit")
+	viper.BindPFlag(keyMaxFields, CrawlCmd.PersistentFlags().Lookup(keyMaxFields))
+
+	CrawlCmd.PersistentFlags().String(keyMutations, string(crawler.MutationsSafe), fmt.Sprintf("Which mutations to perform, one of %v. The safe mode skips mutations matching the destructive patterns", crawler.MutationModes))
+	viper.BindPFlag(keyMutations, CrawlCmd.PersistentFlags().Lookup(keyMutations))
+
+	CrawlCmd.PersistentFlags().Bool(keyConfirm, false, fmt.Sprintf("Confirm that destructive mutations may be performed, required by --%s=%s", keyMutations, crawler.MutationsAll))
+	viper.BindPFlag(keyConfirm, CrawlCmd.PersistentFlags().Lookup(keyConfirm))
+
+	CrawlCmd.PersistentFlags().StringSlice(keyDestructive, crawler.DefaultDestructivePatterns, "Name patterns of the mutations that are destructive")
+	viper.BindPFlag(keyDestructive, CrawlCmd.PersistentFlags().Lookup(keyDestructive))
+
+	CrawlCmd.PersistentFlags().StringSlice(keyProduction, crawler.DefaultProductionHosts, "Host patterns of production targets, which mutations are never performed against")
+	viper.BindPFlag(keyProduction, CrawlCmd.PersistentFlags().Lookup(keyProduction))
+
+	CrawlCmd.PersistentFlags().Bool(keyRecover, false, "Recover the schema from the suggestions in error messages if introspection is disabled")
+	viper.BindPFlag(keyRecover, CrawlCmd.PersistentFlags().Lookup(keyRecover))
+
+	CrawlCmd.PersistentFlags().String(keyWordlist, "", "A file with one name per line to probe when recovering the schema, a built in list is used if not set")
+	viper.BindPFlag(keyWordlist, CrawlCmd.PersistentFlags().Lookup(keyWordlist))
+
+	CrawlCmd.PersistentFlags().String(keyRecord, "", "Record every request and its response to a JSONL cassette file, with secret headers redacted")
+	viper.BindPFlag(keyRecord, CrawlCmd.PersistentFlags().Lookup(keyRecord))
+
+	CrawlCmd.PersistentFlags().String(keyReplay, "", "Serve the responses recorded in a JSONL cassette file instead of sending requests")
+	viper.BindPFlag(keyReplay, CrawlCmd.PersistentFlags().Lookup(keyReplay))
+
+	CrawlCmd.PersistentFlags().String(keyRules, "", "A json file with the rules used to classify responses as denied, the default rules are used if not set")
+	viper.BindPFlag(keyRules, CrawlCmd.PersistentFlags().Lookup(keyRules))
+
+	CrawlCmd.PersistentFlags().String(keyScalars, "", "A json file mapping custom scalar names to the literal values to send for them")
+	viper.BindPFlag(keyScalars, CrawlCmd.PersistentFlags().Lookup(keyScalars))
+
+	CrawlCmd.PersistentFlags().String(keySubscriptions, "", "Crawl subscriptions using the websocket protocol, either \"graphql-transport-ws\" or \"graphql-ws\"")
+	viper.BindPFlag(keySubscriptions, CrawlCmd.PersistentFlags().Lookup(keySubscriptions))
+
+	CrawlCmd.PersistentFlags().String(keySubscriptionUrl, "", "The websocket endpoint for subscriptions, defaults to the target url")
+	viper.BindPFlag(keySubscriptionUrl, CrawlCmd.PersistentFlags().Lookup(keySubscriptionUrl))
+
+	CrawlCmd.PersistentFlags().Int(keySubscriptionTimeout, 5, "The number of seconds to wait for each message of a subscription. A subscription without events before the timeout is considered allowed")
+	viper.BindPFlag(keySubscriptionTimeout, CrawlCmd.PersistentFlags().Lookup(keySubscriptionTimeout))
+
+	crawlRunCmd.Flags().Bool(keyProbe, false, "Request every leaf field of the allowed queries separately to find field level authorization")
+	viper.BindPFlag(keyProbe, crawlRunCmd.Flags().Lookup(keyProbe))
+
+	crawlRunCmd.Flags().Bool(keyFuzz, false, "Send requests with fuzzed arguments for the allowed queries to find input that is not handled cleanly")
+	viper.BindPFlag(keyFuzz, crawlRunCmd.Flags().Lookup(keyFuzz))
+
+	crawlRunCmd.Flags().Int64(keyFuzzSeed, 0, "The seed of the fuzzed arguments, to reproduce an earlier run. A random seed is used if not set")
+	viper.BindPFlag(keyFuzzSeed, crawlRunCmd.Flags().Lookup(keyFuzzSeed))
+
+	crawlRunCmd.Flags().Int(keyFuzzIterations, 20, "The number of fuzzed requests to send per operation")
+	viper.BindPFlag(keyFuzzIterations, crawlRunCmd.Flags().Lookup(keyFuzzIterations))
+
+	crawlRunCmd.Flags().Bool(keyFuzzMutations, false, fmt.Sprintf("Fuzz the allowed mutations as well, repeating them with unusual input. Requires --%s=%s", keyMutations, crawler.MutationsAll))
+	viper.BindPFlag(keyFuzzMutations, crawlRunCmd.Flags().Lookup(keyFuzzMutations))
+
+	crawlRunCmd.Flags().Bool(keyLimits, false, fmt.Sprintf("Probe the highest values of %v that the target accepts, using the first allowed query", crawler.LimitDimensions))
+	viper.BindPFlag(keyLimits, crawlRunCmd.Flags().Lookup(keyLimits))
+
+	crawlRunCmd.Flags().Int(keyLimitsCeiling, crawler.DefaultLimitsCeiling, "The highest value to probe per limit")
+	viper.BindPFlag(keyLimitsCeiling, crawlRunCmd.Flags().Lookup(keyLimitsCeiling))
+
+	crawlRunCmd.Flags().StringArray(keyLimit, []string{}, "The highest value of a limit that the target should accept, formatted like \"dimension=max\". Accepting more is a finding. Can be repeated")
+	viper.BindPFlag(keyLimit, crawlRunCmd.Flags().Lookup(keyLimit))
+
+	crawlRunCmd.Flags().String(keyBaseline, "", "A baseline file to compare the results to, exits with a non-zero status if a denied operation is now allowed")
+	viper.BindPFlag(keyBaseline, crawlRunCmd.Flags().Lookup(keyBaseline))
+
+	crawlRunCmd.Flags().String(keySaveBase, "", "Save the results as a baseline file for later crawls to be compared to")
+	viper.BindPFlag(keySaveBase, crawlRunCmd.Flags().Lookup(keySaveBase))
+
+	crawlRunCmd.Flags().String(keyFormat, string(report.FormatText), fmt.Sprintf("The format of the report, one of %v", report.Formats))
+	viper.BindPFlag(keyFormat, crawlRunCmd.Flags().Lookup(keyFormat))
+
+	crawlRunCmd.Flags().StringP(keyOutput, "o", "", "The file to write the report to, defaults to stdout")
+	viper.BindPFlag(keyOutput, crawlRunCmd.Flags().Lookup(keyOutput))
+
+	crawlRunCmd.Flags().StringSlice(keyFailOn, []string{}, fmt.Sprintf("The outcomes that fail the crawl with exit code %d, any of %v", exitFindings, crawler.FailConditions))
+	viper.BindPFlag(keyFailOn, crawlRunCmd.Flags().Lookup(keyFailOn))
+
+	crawlRunCmd.Flags().Bool(keyMatrix, false, "Print a matrix of the outcome of every operation as every identity")
+	viper.BindPFlag(keyMatrix, crawlRunCmd.Flags().Lookup(keyMatrix))
+
 	CrawlCmd.PersistentFlags().BoolP(keyVerbose, "v", false, "Verbose output")
 	viper.BindPFlag(keyVerbose, CrawlCmd.PersistentFlags().Lookup(keyVerbose))
 }
@@ -50,58 +238,513 @@ var crawlRunCmd = &cobra.Command{
 		addr := viper.GetString(keyTarget)
 		if addr == "" {
 			log.Println("error: no graphql endpoint specified")
-			os.Exit(1)
+			os.Exit(exitConfigError)
+		}
+
+		if viper.GetFloat64(keyRateLimit) < 0 {
+			log.Println("error: rate limit can not be negative")
+			os.Exit(exitConfigError)
+		}
+
+		if viper.GetInt(keyLimitsCeiling) < 1 {
+			log.Printf("error: --%s must be at least 1", keyLimitsCeiling)
+			os.Exit(exitConfigError)
+		}
+
+		format, err := report.ParseFormat(viper.GetString(keyFormat))
+		if err != nil {
+			log.Println("error: ", err)
+			os.Exit(exitConfigError)
+		}
+
+		policy, err := newPolicy()
+		if err != nil {
+			log.Println("error: ", err)
+			os.Exit(exitConfigError)
+		}
+
+		output := viper.GetString(keyOutput)
+
+		// Human readable results are printed unless a machine readable report is written to stdout
+		printResults := format == report.FormatText || output != ""
+		if !printResults {
+			color.NoColor = true
 		}
 
-		ignore := viper.GetStringSlice(keyIgnore)
-		headerSlice := viper.GetStringSlice(keyHeaders)
+		expectedLimits := parseLimits()
+
+		os.Exit(runCrawl(newCrawlerConfig(), runOptions{
+			target:         addr,
+			format:         format,
+			output:         output,
+			printResults:   printResults,
+			policy:         policy,
+			expectedLimits: expectedLimits,
+		}))
+	},
+}
+
+// runOptions are the parsed flags of crawl run that are not part of the crawler config
+type runOptions struct {
+	target string
+	format report.Format
+	output string
+	// Whether the human readable results are printed
+	printResults bool
+	policy       crawler.Policy
+
+	expectedLimits map[crawler.LimitDimension]int
+}
+
+// runCrawl performs the crawl and reports its results, returning the exit code.
+// The recording is closed before returning, as the deferred calls do not run once the command exits
+func runCrawl(cfg crawler.Config, opts runOptions) int {
+	defer cfg.Recorder.Close()
 
-		headers := parseHeaders(headerSlice)
+	// Fuzzing repeats the mutations with unusual input, which is as destructive as performing all of them
+	if viper.GetBool(keyFuzzMutations) && cfg.Mutations != crawler.MutationsAll {
+		log.Printf("error: --%s requires --%s=%s", keyFuzzMutations, keyMutations, crawler.MutationsAll)
+		return exitConfigError
+	}
+
+	c := crawler.New(cfg)
+
+	ops, err := c.Crawl()
+	if err != nil {
+		log.Println("error crawling: ", err)
+		return exitCrawlError
+	}
+
+	var probes []crawler.FieldProbe
+	if viper.GetBool(keyProbe) {
+		probes = c.ProbeFields(ops)
+	}
+
+	r := report.Report{
+		Target:      opts.target,
+		Identities:  c.GetIdentities(),
+		Operations:  ops,
+		FieldProbes: probes,
 
-		cfg := crawler.Config{
-			ClientConfig: introspection.Config{
-				TargetUrl: addr,
-				Headers:   headers,
-			},
-			Ignore: ignore,
+		SuggestionLeak: c.GetSuggestionLeak(),
+	}
+
+	if viper.GetBool(keyFuzz) {
+		seed := viper.GetInt64(keyFuzzSeed)
+		if seed == 0 {
+			seed = time.Now().UnixNano()
 		}
 
-		c := crawler.New(cfg)
+		// The seed is logged to let the run be reproduced, even if the report is never written
+		log.Printf("Fuzzing arguments with seed %d", seed)
+
+		r.FuzzSeed = seed
+		r.FuzzFindings = c.Fuzz(ops, crawler.FuzzConfig{
+			Seed:       seed,
+			Iterations: viper.GetInt(keyFuzzIterations),
+			Mutations:  viper.GetBool(keyFuzzMutations),
+		})
+	}
 
-		ops, err := c.Crawl()
+	if viper.GetBool(keyLimits) {
+		r.Limits, err = c.Limits(ops, crawler.LimitsConfig{
+			Ceiling:  viper.GetInt(keyLimitsCeiling),
+			Expected: opts.expectedLimits,
+		})
 		if err != nil {
-			log.Println("error crawling: ", err)
-			os.Exit(1)
+			log.Println("error: ", err)
+			return exitConfigError
 		}
+	}
+
+	if opts.printResults {
+		report.Write(os.Stdout, report.FormatText, r)
+	}
 
-		for _, op := range ops {
-			op.PrintResult()
+	if viper.GetBool(keyMatrix) && opts.printResults {
+		fmt.Println()
+		crawler.NewMatrix(c.GetIdentities(), ops).Print(os.Stdout)
+	}
+
+	if file := viper.GetString(keySaveBase); file != "" {
+		if err := crawler.NewBaseline(ops).Save(file); err != nil {
+			log.Println("error saving baseline: ", err)
+			return exitCrawlError
 		}
+	}
 
-		if viper.GetBool(keyVerbose) {
-			b, err := json.MarshalIndent(ops, "", "  ")
-			if err != nil {
-				log.Println("error marshalling operations: ", err)
-				os.Exit(1)
-			}
+	var regression bool
+	if file := viper.GetString(keyBaseline); file != "" {
+		baseline, err := crawler.LoadBaseline(file)
+		if err != nil {
+			log.Println("error loading baseline: ", err)
+			return exitConfigError
+		}
+
+		diff := baseline.Compare(ops)
 
-			log.Println(string(b))
+		if opts.printResults {
+			fmt.Println()
+			diff.Print(os.Stdout)
 		}
-	},
+
+		regression = diff.HasRegressions()
+	}
+
+	if viper.GetBool(keyVerbose) && opts.printResults {
+		fmt.Println()
+		report.Write(os.Stdout, report.FormatJSON, r)
+	}
+
+	if err := writeReport(opts.output, opts.format, r); err != nil {
+		log.Println("error writing report: ", err)
+		return exitCrawlError
+	}
+
+	violations := opts.policy.Violations(ops)
+
+	// The summary is kept out of machine readable reports written to stdout
+	summaryOut := os.Stdout
+	if !opts.printResults {
+		summaryOut = os.Stderr
+	}
+
+	fmt.Fprintln(summaryOut)
+	report.Summarize(ops).Print(summaryOut)
+	crawler.PrintViolations(summaryOut, violations)
+
+	if crawler.AllFailed(ops) {
+		log.Println("error: every request failed, the target could not be crawled")
+		return exitCrawlError
+	}
+
+	var limitExceeded bool
+	for _, l := range r.Limits {
+		limitExceeded = limitExceeded || l.Exceeded()
+	}
+
+	if regression || limitExceeded || len(violations) > 0 {
+		return exitFindings
+	}
+
+	return 0
 }
 
-func parseHeaders(headers []string) map[string]string {
-	headerMap := make(map[string]string)
+// writeReport writes the report to the output file, or to stdout if no file is specified.
+// Text reports to stdout are skipped as the results have already been printed
+func writeReport(output string, format report.Format, r report.Report) error {
+	if output == "" {
+		if format == report.FormatText {
+			return nil
+		}
+
+		return report.Write(os.Stdout, format, r)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Text reports written to files should not contain color codes
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	return report.Write(f, format, r)
+}
+
+func newPolicy() (crawler.Policy, error) {
+	var policy crawler.Policy
 
-	for _, header := range headers {
-		split := strings.Split(header, ":")
-		if len(split) != 2 {
-			log.Println("invalid header: ", header)
-			os.Exit(1)
+	for _, s := range viper.GetStringSlice(keyFailOn) {
+		c, err := crawler.ParseFailCondition(s)
+		if err != nil {
+			return crawler.Policy{}, err
 		}
 
-		headerMap[split[0]] = split[1]
+		policy.FailOn = append(policy.FailOn, c)
+	}
+
+	return policy, nil
+}
+
+// newCrawlerConfig builds the crawler config shared by all crawl commands from the flags
+func newCrawlerConfig() crawler.Config {
+	recorder, cassette := newCassette()
+
+	return crawler.Config{
+		ClientConfig: introspection.Config{
+			TargetUrl: viper.GetString(keyTarget),
+			Headers:   parseHeaders(viper.GetStringSlice(keyHeaders)),
+		},
+		ManagerConfig: manager.Config{
+			IncludeOptionalArgs: viper.GetBool(keyOptional),
+			MaxDepth:            viper.GetInt(keyMaxDepth),
+			IncludeNullable:     viper.GetBool(keyNullable),
+			MaxFieldsPerType:    viper.GetInt(keyMaxFields),
+		},
+		SchemaFile:   viper.GetString(keySchema),
+		Include:      parseSelectors(viper.GetStringSlice(keyInclude)),
+		Ignore:       parseSelectors(viper.GetStringSlice(keyIgnore)),
+		Identities:   parseIdentities(),
+		Expectations: parseExpectations(),
+		Mutations:    parseMutationMode(),
+		Concurrency:  viper.GetInt(keyWorkers),
+		BatchSize:    viper.GetInt(keyBatchSize),
+		RateLimit:    viper.GetFloat64(keyRateLimit),
+
+		RecoverSchema: viper.GetBool(keyRecover),
+		Wordlist:      loadWordlist(viper.GetString(keyWordlist)),
+
+		RequestTimeout: time.Duration(viper.GetInt(keyTimeout)) * time.Second,
+
+		Scalars:    loadScalars(viper.GetString(keyScalars)),
+		Classifier: loadClassifier(viper.GetString(keyRules)),
+
+		DestructivePatterns: parsePatterns(keyDestructive),
+		ProductionHosts:     parsePatterns(keyProduction),
+
+		Recorder: recorder,
+		Cassette: cassette,
+
+		SubscriptionProtocol: parseSubscriptionProtocol(viper.GetString(keySubscriptions)),
+		SubscriptionUrl:      viper.GetString(keySubscriptionUrl),
+		SubscriptionTimeout:  time.Duration(viper.GetInt(keySubscriptionTimeout)) * time.Second,
+	}
+}
+
+// parseSelectors compiles the selectors, exiting if any of them is invalid
+func parseSelectors(selectors []string) selector.Set {
+	set, err := selector.ParseSet(selectors)
+	if err != nil {
+		log.Println("error: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	return set
+}
+
+// parsePatterns validates the glob patterns of the flag, exiting if any of them is invalid
+func parsePatterns(key string) []string {
+	patterns := viper.GetStringSlice(key)
+	if err := crawler.ValidatePatterns(patterns); err != nil {
+		log.Printf("error: --%s: %v", key, err)
+		os.Exit(exitConfigError)
+	}
+
+	return patterns
+}
+
+// newCassette creates the recorder or loads the cassette to replay, if specified
+func newCassette() (*client.Recorder, *client.Cassette) {
+	record, replay := viper.GetString(keyRecord), viper.GetString(keyReplay)
+
+	switch {
+	case record != "" && replay != "":
+		log.Printf("error: --%s and --%s can not be used together", keyRecord, keyReplay)
+		os.Exit(exitConfigError)
+	case record != "":
+		r, err := client.NewRecorder(record)
+		if err != nil {
+			log.Println("error: ", err)
+			os.Exit(exitConfigError)
+		}
+
+		return r, nil
+	case replay != "":
+		c, err := client.LoadCassette(replay)
+		if err != nil {
+			log.Println("error: ", err)
+			os.Exit(exitConfigError)
+		}
+
+		return nil, c
+	}
+
+	return nil, nil
+}
+
+// parseMutationMode parses the mutation mode, which requires confirmation to perform destructive mutations
+func parseMutationMode() crawler.MutationMode {
+	mode, err := crawler.ParseMutationMode(viper.GetString(keyMutations))
+	if err != nil {
+		log.Println("error: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	if mode == crawler.MutationsAll && !viper.GetBool(keyConfirm) {
+		log.Printf("error: --%s=%s performs destructive mutations and requires --%s", keyMutations, crawler.MutationsAll, keyConfirm)
+		os.Exit(exitConfigError)
+	}
+
+	return mode
+}
+
+func parseSubscriptionProtocol(protocol string) client.WSProtocol {
+	switch client.WSProtocol(protocol) {
+	case "", client.GraphQLTransportWS, client.GraphQLWS:
+		return client.WSProtocol(protocol)
+	}
+
+	log.Println("invalid subscription protocol: ", protocol)
+	os.Exit(exitConfigError)
+
+	return ""
+}
+
+// loadClassifier reads the classifier rules from a file, if specified
+func loadClassifier(file string) *classifier.Classifier {
+	if file == "" {
+		return nil
+	}
+
+	c, err := classifier.Load(file)
+	if err != nil {
+		log.Println("error loading rules: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	return c
+}
+
+// loadWordlist reads the names to recover the schema with from a file, if specified
+func loadWordlist(file string) []string {
+	if file == "" {
+		return nil
+	}
+
+	words, err := introspection.LoadWordlist(file)
+	if err != nil {
+		log.Println("error: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	return words
+}
+
+// loadScalars reads the literal scalar values from a json file, if specified
+func loadScalars(file string) map[string]any {
+	if file == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		log.Println("error reading scalars file: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(b, &values); err != nil {
+		log.Println("error parsing scalars file: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	return values
+}
+
+// parseHeaders parses the headers, exiting if any of them is invalid
+func parseHeaders(headers []string) map[string]string {
+	headerMap, err := utils.ParseHeaders(headers)
+	if err != nil {
+		log.Println("error: ", err)
+		os.Exit(exitConfigError)
 	}
 
 	return headerMap
 }
+
+// parseIdentities collects the identities from the config file and the identity flags
+func parseIdentities() []crawler.Identity {
+	var identities []crawler.Identity
+	if err := viper.UnmarshalKey(keyIdentities, &identities); err != nil {
+		log.Println("invalid identities in config: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	for _, identity := range viper.GetStringSlice(keyIdentity) {
+		name, headers, _ := strings.Cut(identity, "=")
+		if name == "" {
+			log.Println("invalid identity: ", identity)
+			os.Exit(exitConfigError)
+		}
+
+		var headerSlice []string
+		if headers != "" {
+			headerSlice = strings.Split(headers, ";")
+		}
+
+		identities = append(identities, crawler.Identity{
+			Name:    name,
+			Headers: parseHeaders(headerSlice),
+		})
+	}
+
+	return identities
+}
+
+// parseLimits parses the expected maximum of every limit given
+func parseLimits() map[crawler.LimitDimension]int {
+	limits := make(map[crawler.LimitDimension]int)
+
+	for _, limit := range viper.GetStringSlice(keyLimit) {
+		name, value, _ := strings.Cut(limit, "=")
+
+		d, err := crawler.ParseLimitDimension(name)
+		if err != nil {
+			log.Println("error: ", err)
+			os.Exit(exitConfigError)
+		}
+
+		max, err := strconv.Atoi(value)
+		if err != nil || max < 1 {
+			log.Printf("invalid maximum for limit %s: %s", name, value)
+			os.Exit(exitConfigError)
+		}
+
+		limits[d] = max
+	}
+
+	return limits
+}
+
+// parseExpectations merges the expectations of the config file with the ones given as flags
+func parseExpectations() map[string]crawler.Expectation {
+	expectations := make(map[string]crawler.Expectation)
+
+	set := func(name string, expectation string) {
+		e, err := crawler.ParseExpectation(expectation)
+		if name == "" || err != nil {
+			log.Printf("invalid expectation for operation \"%s\": %v", name, err)
+			os.Exit(exitConfigError)
+		}
+
+		expectations[name] = e
+	}
+
+	// The config file lists the operations per expectation, as viper does not keep the case of map keys
+	var byExpectation map[string][]string
+	if err := viper.UnmarshalKey(keyExpects, &byExpectation); err != nil {
+		log.Println("invalid expectations in config: ", err)
+		os.Exit(exitConfigError)
+	}
+
+	for expectation, names := range byExpectation {
+		for _, name := range names {
+			set(name, expectation)
+		}
+	}
+
+	for _, expect := range viper.GetStringSlice(keyExpect) {
+		name, expectation, _ := strings.Cut(expect, "=")
+		set(name, expectation)
+	}
+
+	for _, name := range viper.GetStringSlice(keyExpectPub) {
+		set(name, string(crawler.ExpectPublic))
+	}
+
+	return expectations
+}
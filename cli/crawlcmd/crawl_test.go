@@ -0,0 +1,106 @@
+package crawlcli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func Test_parseIdentities(t *testing.T) {
+	tests := []struct {
+		name   string
+		config []map[string]any
+		flags  []string
+		want   []crawler.Identity
+	}{
+		{
+			name: "none",
+			want: nil,
+		},
+		{
+			name:  "flags",
+			flags: []string{"anonymous", "admin=Authorization:admin;X-Tenant:acme"},
+			want: []crawler.Identity{
+				{Name: "anonymous", Headers: map[string]string{}},
+				{Name: "admin", Headers: map[string]string{"Authorization": "admin", "X-Tenant": "acme"}},
+			},
+		},
+		{
+			name: "config file and flags",
+			config: []map[string]any{
+				{"name": "user", "headers": map[string]any{"Authorization": "user"}},
+			},
+			flags: []string{"admin=Authorization:admin"},
+			want: []crawler.Identity{
+				{Name: "user", Headers: map[string]string{"Authorization": "user"}},
+				{Name: "admin", Headers: map[string]string{"Authorization": "admin"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set(keyIdentities, tt.config)
+			viper.Set(keyIdentity, tt.flags)
+			t.Cleanup(func() {
+				viper.Set(keyIdentities, nil)
+				viper.Set(keyIdentity, nil)
+			})
+
+			if got := parseIdentities(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIdentities(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseSelectors(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		flags []string
+		want  []string
+	}{
+		{
+			name:  "ignore",
+			key:   keyIgnore,
+			flags: []string{"admin*", "kind:mutation&returns:User"},
+			want:  []string{"admin*", "kind:mutation&returns:User"},
+		},
+		{
+			name:  "comma in regex",
+			key:   keyIgnore,
+			flags: []string{"/^a{1,3}$/", "/(a|b),c/"},
+			want:  []string{"/^a{1,3}$/", "/(a|b),c/"},
+		},
+		{
+			name:  "include comma in regex",
+			key:   keyInclude,
+			flags: []string{"/(a|b),c/"},
+			want:  []string{"/(a|b),c/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := CrawlCmd.PersistentFlags().Lookup(tt.key)
+			for _, f := range tt.flags {
+				if err := flag.Value.Set(f); err != nil {
+					t.Fatalf("Set(%q), got %v", f, err)
+				}
+			}
+			flag.Changed = true
+			t.Cleanup(func() {
+				flag.Value.(pflag.SliceValue).Replace(nil)
+				flag.Changed = false
+			})
+
+			if got := parseSelectors(viper.GetStringSlice(tt.key)).Strings(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSelectors(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,25 @@
 package cli
 
 import (
+	"log"
+	"os"
 	"strings"
 
 	crawlcli "github.com/TheLeeeo/gql-test-suite/cli/crawlcmd"
+	"github.com/TheLeeeo/gql-test-suite/cli/schemacmd"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var configFile string
+
 func init() {
+	cobra.OnInitialize(readConfigFile)
+
+	RootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "A yaml or json file to read the configuration from")
+
 	RootCmd.AddCommand(crawlcli.CrawlCmd)
+	RootCmd.AddCommand(schemacmd.SchemaCmd)
 	RootCmd.AddCommand(executeFileCmd)
 
 	viper.AutomaticEnv()
@@ -25,3 +35,16 @@ by generating queries and mutations based on the schema.`,
 		cmd.Help()
 	},
 }
+
+// readConfigFile loads the config file, if specified, letting it provide the value of any flag
+func readConfigFile() {
+	if configFile == "" {
+		return
+	}
+
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Println("error reading config file: ", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,80 @@
+package schemacmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat     string
+	failOnBreaking bool
+)
+
+func init() {
+	SchemaCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringSliceVarP(&headers, "headers", "H", []string{}, "Headers to send with the introspection queries, formatted like \"k1:v1,k2,v2\"")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "The format of the changes, either \"text\" or \"json\"")
+	diffCmd.Flags().BoolVar(&failOnBreaking, "fail-on-breaking", false, "Exit with status 1 if any change is breaking")
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff old new",
+	Short: "Show the changes between two schemas",
+	Long: `Show the changes between two schemas, classified as breaking, dangerous or safe.
+Each schema is either a graphql endpoint to introspect (http:// or https://),
+a saved introspection result (.json), an SDL file or a directory of SDL files.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if diffFormat != "text" && diffFormat != "json" {
+			log.Println("invalid format: ", diffFormat)
+			os.Exit(1)
+		}
+
+		old, err := loadSource(args[0])
+		if err != nil {
+			log.Printf("error loading old schema %s: %v", args[0], err)
+			os.Exit(1)
+		}
+
+		new, err := loadSource(args[1])
+		if err != nil {
+			log.Printf("error loading new schema %s: %v", args[1], err)
+			os.Exit(1)
+		}
+
+		changes := diff.Compare(old, new)
+
+		if diffFormat == "json" {
+			b, err := json.MarshalIndent(changes, "", "  ")
+			if err != nil {
+				log.Println("error marshalling changes: ", err)
+				os.Exit(1)
+			}
+
+			fmt.Println(string(b))
+		} else {
+			diff.Print(os.Stdout, changes)
+		}
+
+		if failOnBreaking && diff.HasBreaking(changes) {
+			os.Exit(1)
+		}
+	},
+}
+
+// loadSource introspects the source if it is an url, otherwise loads it as a file
+func loadSource(source string) (*schema.Schema, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return loadSchema(source, "")
+	}
+
+	return loadSchema("", source)
+}
@@ -0,0 +1,71 @@
+package schemacmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	wordlistFile string
+	maxProbes    int
+)
+
+func init() {
+	SchemaCmd.AddCommand(recoverCmd)
+
+	recoverCmd.Flags().StringVarP(&targetURL, "target-url", "t", "", "The graphql endpoint to recover the schema of")
+	recoverCmd.Flags().StringSliceVarP(&headers, "headers", "H", []string{}, "Headers to send with the probe queries, formatted like \"k1:v1,k2,v2\"")
+	recoverCmd.Flags().StringVar(&wordlistFile, "wordlist", "", "A file with one name per line to probe, a built in list is used if not set")
+	recoverCmd.Flags().IntVar(&maxProbes, "max-probes", introspection.DefaultMaxProbes, "The maximum number of probe queries to send")
+}
+
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Recover the schema of an endpoint with introspection disabled and print it as SDL",
+	Long: `Recover the schema of an endpoint with introspection disabled and print it as SDL.
+Names from the wordlist are probed and the validation errors are read for suggested names and types,
+until no new names are found. The recovered schema only contains what could be found this way.
+The probe queries always fail validation, so nothing is executed on the endpoint.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if targetURL == "" {
+			log.Println("error: ", introspection.ErrNoTargetAddr)
+			os.Exit(1)
+		}
+
+		var words []string
+		if wordlistFile != "" {
+			var err error
+			words, err = introspection.LoadWordlist(wordlistFile)
+			if err != nil {
+				log.Println("error: ", err)
+				os.Exit(1)
+			}
+		}
+
+		rec, err := introspection.New(introspection.Config{
+			TargetUrl: targetURL,
+			Headers:   parseHeaders(headers),
+		}).RecoverSchema(introspection.RecoveryConfig{
+			Wordlist:  words,
+			MaxProbes: maxProbes,
+		})
+		if err != nil {
+			log.Println("error recovering schema: ", err)
+			os.Exit(1)
+		}
+
+		log.Printf("Recovered the schema with %d probes", rec.Probes)
+		if rec.Leak != nil {
+			log.Printf("The endpoint suggests names of its schema in errors, %d names were leaked", rec.Leak.Names)
+		}
+
+		if err := sdl.Print(os.Stdout, rec.Schema); err != nil {
+			log.Println("error printing schema: ", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,77 @@
+package schemacmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"github.com/TheLeeeo/gql-test-suite/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	targetURL  string
+	headers    []string
+	schemaFile string
+)
+
+func init() {
+	SchemaCmd.AddCommand(printCmd)
+
+	printCmd.Flags().StringVarP(&targetURL, "target-url", "t", "", "The graphql endpoint to introspect")
+	printCmd.Flags().StringSliceVarP(&headers, "headers", "H", []string{}, "Headers to send with the introspection query, formatted like \"k1:v1,k2,v2\"")
+	printCmd.Flags().StringVar(&schemaFile, "schema", "", "Print a saved introspection result (.json), an SDL file or a directory of SDL files instead of introspecting the target")
+}
+
+var SchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "For inspecting the schema of a graphql endpoint",
+}
+
+var printCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the schema as SDL",
+	Long: `Print the schema as SDL. Types, fields and values are sorted by name,
+so the output only changes when the schema does and can be committed as a snapshot.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := loadSchema(targetURL, schemaFile)
+		if err != nil {
+			log.Println("error loading schema: ", err)
+			os.Exit(1)
+		}
+
+		if err := sdl.Print(os.Stdout, s); err != nil {
+			log.Println("error printing schema: ", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// loadSchema loads the schema from the file if set, otherwise by introspecting the target
+func loadSchema(target string, file string) (*schema.Schema, error) {
+	if file != "" {
+		return introspection.LoadSchema(file)
+	}
+
+	if target == "" {
+		return nil, introspection.ErrNoTargetAddr
+	}
+
+	return introspection.New(introspection.Config{
+		TargetUrl: target,
+		Headers:   parseHeaders(headers),
+	}).FetchSchema()
+}
+
+// parseHeaders parses the headers, exiting if any of them is invalid
+func parseHeaders(headers []string) map[string]string {
+	headerMap, err := utils.ParseHeaders(headers)
+	if err != nil {
+		log.Println("error: ", err)
+		os.Exit(1)
+	}
+
+	return headerMap
+}
@@ -0,0 +1,315 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Interaction is a request and its response, stored as a line of a cassette
+type Interaction struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+
+	// The requests of an array batch, in place of the query, operation name, variables and extensions
+	Batch []Payload `json:"batch,omitempty"`
+
+	Headers map[string]string `json:"headers,omitempty"`
+
+	StatusCode int    `json:"statusCode,omitempty"`
+	Body       string `json:"body,omitempty"`
+	// Set if no response was received
+	Error string `json:"error,omitempty"`
+
+	DurationMs int64 `json:"durationMs"`
+}
+
+// Parts of header names that mark their values as secrets
+var secretHeaderParts = []string{"auth", "cookie", "token", "secret", "key", "session", "password"}
+
+// secretHeader reports whether the value of the header may be a secret
+func secretHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range secretHeaderParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeaders replaces the values of headers that may contain secrets with their label.
+// The labels keep requests sent as different identities apart when replaying, without revealing anything about the values
+func redactHeaders(headers map[string]string, label func(value string) string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	// Labels are handed out in order of first sight, so the headers are visited in a fixed order
+	names := maps.Keys(headers)
+	slices.Sort(names)
+
+	redacted := make(map[string]string, len(headers))
+	for _, k := range names {
+		v := headers[k]
+		redacted[k] = v
+		if secretHeader(k) {
+			redacted[k] = label(v)
+		}
+	}
+
+	return redacted
+}
+
+// interactionKey identifies the request of an interaction, the headers must already be redacted
+func interactionKey(i Interaction) string {
+	// Maps are marshalled with sorted keys, making the key independent of their order
+	h, _ := json.Marshal(i.Headers)
+
+	if len(i.Batch) > 0 {
+		keys := make([]string, len(i.Batch))
+		for j, p := range i.Batch {
+			keys[j] = payloadKey(p)
+		}
+
+		return fmt.Sprintf("batch\n%s\n%s", strings.Join(keys, "\n"), h)
+	}
+
+	return fmt.Sprintf("%s\n%s", payloadKey(Payload{
+		Query:         i.Query,
+		OperationName: i.OperationName,
+		Variables:     i.Variables,
+		Extensions:    i.Extensions,
+	}), h)
+}
+
+// payloadKey identifies a single request
+func payloadKey(p Payload) string {
+	// Empty variables are omitted when recorded
+	if len(p.Variables) == 0 {
+		p.Variables = nil
+	}
+
+	v, _ := json.Marshal(p.Variables)
+	key := fmt.Sprintf("%s\n%s", p.Query, v)
+
+	// Only added when set, keeping the keys of requests recorded before they were
+	if p.OperationName != "" || len(p.Extensions) > 0 {
+		e, _ := json.Marshal(p.Extensions)
+		key += fmt.Sprintf("\n%s\n%s", p.OperationName, e)
+	}
+
+	return key
+}
+
+// Recorder writes every interaction of the clients using it to a JSONL file
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+
+	// The labels of the secret header values, numbered in the order they were first recorded
+	labels map[string]string
+}
+
+// NewRecorder creates the cassette file, replacing it if it exists
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cassette: %v", err)
+	}
+
+	return &Recorder{
+		file:   f,
+		enc:    json.NewEncoder(f),
+		labels: make(map[string]string),
+	}, nil
+}
+
+// Record writes the interaction with the secrets of its headers redacted
+func (r *Recorder) Record(i Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i.Headers = redactHeaders(i.Headers, r.label)
+
+	return r.enc.Encode(i)
+}
+
+// label returns the label of the secret value, the caller must hold the lock of the recorder
+func (r *Recorder) label(value string) string {
+	if l, ok := r.labels[value]; ok {
+		return l
+	}
+
+	l := fmt.Sprintf("REDACTED:%d", len(r.labels)+1)
+	r.labels[value] = l
+
+	return l
+}
+
+// Close flushes the recorded interactions to disk and closes the cassette, it does nothing if there is no recorder
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.file.Sync(); err != nil {
+		r.file.Close()
+		return fmt.Errorf("error writing cassette: %v", err)
+	}
+
+	return r.file.Close()
+}
+
+// Cassette serves recorded responses in place of sending requests
+type Cassette struct {
+	mu sync.Mutex
+
+	// The interactions not yet replayed, in the order they were recorded, per request
+	interactions map[string][]Interaction
+
+	// The labels of the secret header values in the order they were first recorded
+	labels []string
+	// The labels that the secret header values of the replayed requests have been matched to
+	bound map[string]string
+	used  map[string]bool
+}
+
+// LoadCassette reads the interactions of a JSONL file written by a Recorder
+func LoadCassette(path string) (*Cassette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening cassette: %v", err)
+	}
+	defer f.Close()
+
+	c := &Cassette{
+		interactions: make(map[string][]Interaction),
+		bound:        make(map[string]string),
+		used:         make(map[string]bool),
+	}
+	labels := make(map[string]bool)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var i Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("error parsing line %d of cassette: %v", line, err)
+		}
+
+		key := interactionKey(i)
+		c.interactions[key] = append(c.interactions[key], i)
+
+		for k, v := range i.Headers {
+			if secretHeader(k) && !labels[v] {
+				labels[v] = true
+				c.labels = append(c.labels, v)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cassette: %v", err)
+	}
+
+	return c, nil
+}
+
+// ReplayedError is the recorded error of an interaction, of which only the message is kept
+type ReplayedError struct {
+	Message string
+}
+
+func (e *ReplayedError) Error() string {
+	return e.Message
+}
+
+// Replay returns the recorded interaction of the request.
+// Identical requests get the recorded interactions in order, with the last one repeated once all have been replayed
+func (c *Cassette) Replay(req *Request) (Interaction, error) {
+	return c.replay(newInteraction("", req, time.Time{}))
+}
+
+// ReplayBatch returns the recorded interaction of the array batch of the requests
+func (c *Cassette) ReplayBatch(reqs []*Request) (Interaction, error) {
+	return c.replay(newBatchInteraction("", reqs, time.Time{}))
+}
+
+func (c *Cassette) replay(i Interaction) (Interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var unbound []string
+	for k, v := range i.Headers {
+		if _, ok := c.bound[v]; secretHeader(k) && !ok && !slices.Contains(unbound, v) {
+			unbound = append(unbound, v)
+		}
+	}
+	slices.Sort(unbound)
+
+	key, ok := c.bind(i, unbound)
+	if !ok {
+		return Interaction{}, fmt.Errorf("no recorded response for the request")
+	}
+
+	recorded := c.interactions[key]
+	if len(recorded) > 1 {
+		c.interactions[key] = recorded[1:]
+	}
+
+	return recorded[0], nil
+}
+
+// bind matches the secret values not yet seen to the unused labels of the cassette, so that the request has a recorded response.
+// The labels are tried in the order they were recorded, matching the values of a run performed in the same order as the recorded one.
+// Returns the key of the recorded interactions of the request, the caller must hold the lock of the cassette
+func (c *Cassette) bind(i Interaction, unbound []string) (string, bool) {
+	if len(unbound) == 0 {
+		r := i
+		r.Headers = redactHeaders(i.Headers, func(v string) string { return c.bound[v] })
+
+		key := interactionKey(r)
+		return key, len(c.interactions[key]) > 0
+	}
+
+	value := unbound[0]
+	for _, l := range c.labels {
+		if c.used[l] {
+			continue
+		}
+
+		c.bound[value], c.used[l] = l, true
+		if key, ok := c.bind(i, unbound[1:]); ok {
+			return key, true
+		}
+		delete(c.bound, value)
+		delete(c.used, l)
+	}
+
+	return "", false
+}
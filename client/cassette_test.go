@@ -0,0 +1,223 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_RecorderRedactsHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, headers := range []map[string]string{
+		{"Authorization": "Bearer admin", "X-Api-Key": "admin", "X-Tenant": "acme"},
+		{"Authorization": "Bearer user"},
+		{"Authorization": "Bearer admin"},
+	} {
+		if err := recorder.Record(Interaction{Query: "query{me{id}}", Headers: headers}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder.Close()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "admin") || strings.Contains(string(b), "user") {
+		t.Errorf("Record(), got a secret in the cassette:\n%s", b)
+	}
+
+	want := []map[string]string{
+		{"Authorization": "REDACTED:1", "X-Api-Key": "REDACTED:2", "X-Tenant": "acme"},
+		{"Authorization": "REDACTED:3"},
+		{"Authorization": "REDACTED:1"},
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Record(), got %d lines, want %d", len(lines), len(want))
+	}
+
+	for i, line := range lines {
+		var got Interaction
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got.Headers, want[i]) {
+			t.Errorf("Record(), got headers %v, want %v", got.Headers, want[i])
+		}
+	}
+}
+
+func Test_RecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"message":"Unauthenticated"}]}`))
+			return
+		}
+
+		w.Write([]byte(`{"data":{"me":{"id":"1"}}}`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authenticated := NewRequest("query{me{id}}", map[string]any{"first": 1})
+	authenticated.Headers = map[string]string{"Authorization": "token"}
+	anonymous := NewRequest("query{me{id}}", map[string]any{"first": 1})
+
+	c := New(server.URL)
+	c.Recorder = recorder
+	for _, req := range []*Request{authenticated, anonymous} {
+		if _, err := c.Execute(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder.Close()
+	server.Close()
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c = New(server.URL)
+	c.Cassette = cassette
+
+	// The secrets are not in the cassette, so a different one is matched to the recorded identity
+	rotated := NewRequest("query{me{id}}", map[string]any{"first": 1})
+	rotated.Headers = map[string]string{"Authorization": "rotated"}
+
+	resp, err := c.Execute(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Data["me"] == nil {
+		t.Errorf("Execute(), got %d %v, want the authenticated response", resp.StatusCode, resp.Data)
+	}
+
+	resp, err = c.Execute(anonymous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized || len(resp.Errors) != 1 {
+		t.Errorf("Execute(), got %d %v, want the anonymous response", resp.StatusCode, resp.Errors)
+	}
+
+	if _, err := c.Execute(NewRequest("query{other}", nil)); err == nil {
+		t.Errorf("Execute(), got no error for a request that was not recorded")
+	}
+}
+
+func Test_ExecuteBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payloads []Payload
+		if err := json.NewDecoder(r.Body).Decode(&payloads); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":[{"message":"batching is not supported"}]}`))
+			return
+		}
+
+		if len(payloads) > 2 {
+			w.Write([]byte(`[{"data":{"n":0}}]`))
+			return
+		}
+
+		responses := make([]map[string]any, len(payloads))
+		for i, p := range payloads {
+			responses[i] = map[string]any{"data": map[string]any{"n": p.Variables["n"]}}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	batch := func(n int) []*Request {
+		reqs := make([]*Request, n)
+		for i := range reqs {
+			reqs[i] = NewRequest("query($n: Int){n}", map[string]any{"n": i})
+		}
+		return reqs
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(server.URL)
+	c.Recorder = recorder
+
+	resps, err := c.ExecuteBatch(batch(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, resp := range resps {
+		if resp.Data["n"] != float64(i) {
+			t.Errorf("ExecuteBatch(), got %v for request %d", resp.Data["n"], i)
+		}
+	}
+
+	if _, err := c.ExecuteBatch(batch(3)); err == nil {
+		t.Errorf("ExecuteBatch(), got no error for a response of the wrong size")
+	}
+
+	mixed := batch(2)
+	mixed[1].Headers = map[string]string{"Authorization": "token"}
+	if _, err := c.ExecuteBatch(mixed); err == nil {
+		t.Errorf("ExecuteBatch(), got no error for requests with different headers")
+	}
+	recorder.Close()
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c = New(server.URL)
+	c.Cassette = cassette
+
+	resps, err = c.ExecuteBatch(batch(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resps) != 2 || resps[1].Data["n"] != float64(1) {
+		t.Errorf("ExecuteBatch(), got %v when replaying", resps)
+	}
+
+	if _, err := c.Execute(batch(1)[0]); err == nil {
+		t.Errorf("Execute(), got a replayed batch for a single request")
+	}
+}
+
+func Test_ParseBatchResponse(t *testing.T) {
+	if _, err := parseBatchResponse(http.StatusBadRequest, []byte(`{"errors":[{"message":"batching is not supported"}]}`), 2); err == nil {
+		t.Errorf("parseBatchResponse(), got no error for a single error response")
+	}
+
+	resps, err := parseBatchResponse(http.StatusOK, []byte(`[{"data":{"a":1}},{"errors":[{"message":"denied"}]}]`), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resps[0].Data["a"] != float64(1) || len(resps[1].Errors) != 1 || resps[1].StatusCode != http.StatusOK {
+		t.Errorf("parseBatchResponse(), got %v and %v", resps[0], resps[1])
+	}
+}
@@ -3,15 +3,27 @@ package client
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/TheLeeeo/gql-test-suite/utils"
+	"golang.org/x/exp/maps"
 )
 
 type Client struct {
 	Endpoint string
+
+	// How long to wait for the response of a request, 0 means no limit
+	Timeout time.Duration
+
+	// Records every request and its response if set
+	Recorder *Recorder
+
+	// Serves the recorded responses instead of sending requests if set
+	Cassette *Cassette
 }
 
 func New(endpoint string) *Client {
@@ -21,44 +33,193 @@ func New(endpoint string) *Client {
 }
 
 func (c *Client) Execute(req *Request) (*Response, error) {
+	if c.Cassette != nil {
+		i, err := c.Cassette.Replay(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if i.Error != "" {
+			return nil, fmt.Errorf("error sending request: %w", &ReplayedError{Message: i.Error})
+		}
+
+		return parseResponse(i.StatusCode, []byte(i.Body))
+	}
+
+	start := time.Now()
+	statusCode, responseBody, err := c.send(req.Build(), req.Headers)
+
+	if c.Recorder != nil {
+		i := newInteraction(c.Endpoint, req, start)
+		i.StatusCode = statusCode
+		i.Body = string(responseBody)
+		if err != nil {
+			i.Error = err.Error()
+		}
+
+		if err := c.Recorder.Record(i); err != nil {
+			return nil, fmt.Errorf("error recording request: %v", err)
+		}
+	}
+
+	// The error is wrapped to let callers tell timeouts apart from other errors
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+
+	return parseResponse(statusCode, responseBody)
+}
+
+// ExecuteBatch sends the requests as one array batch, returning the response of every request in the same order.
+// The batch is a single http request, so the requests must have the same headers
+func (c *Client) ExecuteBatch(reqs []*Request) ([]*Response, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	for _, req := range reqs[1:] {
+		if !maps.Equal(req.Headers, reqs[0].Headers) {
+			return nil, fmt.Errorf("the requests of a batch must have the same headers")
+		}
+	}
+
+	if c.Cassette != nil {
+		i, err := c.Cassette.ReplayBatch(reqs)
+		if err != nil {
+			return nil, err
+		}
+
+		if i.Error != "" {
+			return nil, fmt.Errorf("error sending batch: %w", &ReplayedError{Message: i.Error})
+		}
+
+		return parseBatchResponse(i.StatusCode, []byte(i.Body), len(reqs))
+	}
+
+	start := time.Now()
+	statusCode, responseBody, err := c.send(BuildBatch(reqs), reqs[0].Headers)
+
+	if c.Recorder != nil {
+		i := newBatchInteraction(c.Endpoint, reqs, start)
+		i.StatusCode = statusCode
+		i.Body = string(responseBody)
+		if err != nil {
+			i.Error = err.Error()
+		}
+
+		if err := c.Recorder.Record(i); err != nil {
+			return nil, fmt.Errorf("error recording batch: %v", err)
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error sending batch: %w", err)
+	}
+
+	return parseBatchResponse(statusCode, responseBody, len(reqs))
+}
+
+// send posts the body, returning the status code and body of the response
+func (c *Client) send(body []byte, headers map[string]string) (int, []byte, error) {
 	// Create the http request
-	requestBody := bytes.NewBuffer(req.Build())
+	requestBody := bytes.NewBuffer(body)
 	httpRequest, err := http.NewRequest("POST", c.Endpoint, requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %v", err)
+		return 0, nil, fmt.Errorf("error creating request: %v", err)
 	}
 	httpRequest.Header.Add("Content-Type", "application/json")
 
-	for k, v := range req.Headers {
+	for k, v := range headers {
 		httpRequest.Header.Add(k, v)
 	}
 
 	// Send the request
-	client := &http.Client{}
-	// fmt.Println(httpRequest)
+	client := &http.Client{Timeout: c.Timeout}
 	httpResponse, err := client.Do(httpRequest)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request: %v", err)
+		return 0, nil, err
 	}
 
 	// Read the response body
 	defer httpResponse.Body.Close()
 	responseBody, err := io.ReadAll(httpResponse.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
+		return httpResponse.StatusCode, nil, fmt.Errorf("error reading response body: %v", err)
 	}
 
-	// Try to parse the response into a gql response
-	resp, err := Parse(responseBody)
+	return httpResponse.StatusCode, responseBody, nil
+}
+
+// parseResponse tries to parse the body into a gql response.
+// The status code is kept for responses that are not graphql, eg. a plain 401 from a gateway
+func parseResponse(statusCode int, body []byte) (*Response, error) {
+	resp, err := Parse(body)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing response: %v", err)
+		return &Response{StatusCode: statusCode}, fmt.Errorf("error parsing response: %v", err)
 	}
 
-	resp.StatusCode = httpResponse.StatusCode
+	resp.StatusCode = statusCode
 
 	return resp, nil
 }
 
+// parseBatchResponse maps the elements of the array response to the requests, which the server answers in order.
+// Servers that do not support batching answer with a single error instead of an array
+func parseBatchResponse(statusCode int, body []byte, size int) ([]*Response, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(body, &elements); err != nil {
+		if resp, err := Parse(body); err == nil && len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("the batch was rejected with status code %d: %s", statusCode, resp.Errors[0].Message)
+		}
+
+		return nil, fmt.Errorf("error parsing batch response with status code %d: %v", statusCode, err)
+	}
+
+	if len(elements) != size {
+		return nil, fmt.Errorf("the batch of %d requests was answered with %d responses", size, len(elements))
+	}
+
+	responses := make([]*Response, size)
+	for i, e := range elements {
+		resp, err := parseResponse(statusCode, e)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing response %d of batch: %v", i, err)
+		}
+
+		responses[i] = resp
+	}
+
+	return responses, nil
+}
+
+func newInteraction(endpoint string, req *Request, start time.Time) Interaction {
+	return Interaction{
+		Time:          start,
+		Endpoint:      endpoint,
+		Query:         req.Body,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		Extensions:    req.Extensions,
+		Headers:       req.Headers,
+		DurationMs:    time.Since(start).Milliseconds(),
+	}
+}
+
+func newBatchInteraction(endpoint string, reqs []*Request, start time.Time) Interaction {
+	batch := make([]Payload, len(reqs))
+	for i, req := range reqs {
+		batch[i] = req.payload()
+	}
+
+	return Interaction{
+		Time:       start,
+		Endpoint:   endpoint,
+		Batch:      batch,
+		Headers:    reqs[0].Headers,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
+
 func (c *Client) ExecuteFile(filename string) (*Response, error) {
 	q := utils.LoadQuery(filename)
 	req := NewRequest(q, map[string]any{"params": map[string]any{}})
@@ -2,19 +2,31 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"golang.org/x/exp/slices"
 )
 
 type Request struct {
 	Body      string
 	Variables map[string]any
 	Headers   map[string]string
+
+	// Selects the operation to execute of a document with several operations
+	OperationName string
+
+	// Extensions of the request, eg. the hash of a persisted query
+	Extensions map[string]any
 }
 
 type RequestType string
 
 const (
-	QueryRequest    RequestType = "query"
-	MutationRequest RequestType = "mutation"
+	QueryRequest        RequestType = "query"
+	MutationRequest     RequestType = "mutation"
+	SubscriptionRequest RequestType = "subscription"
 )
 
 func NewRequest(body string, variables map[string]any) *Request {
@@ -24,22 +36,102 @@ func NewRequest(body string, variables map[string]any) *Request {
 	}
 }
 
+// Payload is a request as sent to the server, as specified by https://graphql.github.io/graphql-over-http/draft/#sec-Request-Parameters
+type Payload struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+// payload is the request as sent to the server
+func (r *Request) payload() Payload {
+	return Payload{
+		Query:         r.Body,
+		OperationName: r.OperationName,
+		Variables:     r.Variables,
+		Extensions:    r.Extensions,
+	}
+}
+
 // Build compiles the request into a byte array that can be sent to the server.
 func (r *Request) Build() []byte {
-	type requestInternal struct {
-		Query     string         `json:"query"`
-		Variables map[string]any `json:"variables"`
+	b, err := json.Marshal(r.payload())
+	if err != nil {
+		panic(err)
 	}
 
-	req := &requestInternal{
-		Query:     r.Body,
-		Variables: r.Variables,
+	return b
+}
+
+// BuildBatch compiles the requests into an array batch that can be sent to the server as one request
+func BuildBatch(reqs []*Request) []byte {
+	payloads := make([]Payload, len(reqs))
+	for i, r := range reqs {
+		payloads[i] = r.payload()
 	}
 
-	b, err := json.Marshal(req)
+	b, err := json.Marshal(payloads)
 	if err != nil {
 		panic(err)
 	}
 
 	return b
 }
+
+// NameOperation names an anonymous operation, eg. "query { a }" becomes "query A { a }".
+// Query shorthands like "{ a }" become named queries. The body may contain fragment definitions besides the operation
+func NameOperation(body string, name string) (string, error) {
+	trimmed := strings.TrimSpace(body)
+
+	ops, err := sdl.Operations(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("error parsing the body: %v", err)
+	}
+
+	switch {
+	case len(ops) == 0:
+		return "", fmt.Errorf("the body is not an operation")
+	case len(ops) > 1:
+		return "", fmt.Errorf("the body contains %d operations", len(ops))
+	case ops[0].Name != "":
+		return "", fmt.Errorf("the operation is already named")
+	}
+
+	before, after := trimmed[:ops[0].NameOffset], trimmed[ops[0].NameOffset:]
+	if ops[0].Type == "" {
+		return fmt.Sprintf("%s%s %s %s", before, QueryRequest, name, after), nil
+	}
+
+	return fmt.Sprintf("%s %s%s", before, name, after), nil
+}
+
+// NewDocumentRequest creates a request of a document with several operations, executing the named one.
+// The operations are named by the keys of the map and must be anonymous
+func NewDocumentRequest(operations map[string]string, operationName string, variables map[string]any) (*Request, error) {
+	if _, ok := operations[operationName]; !ok {
+		return nil, fmt.Errorf("the document has no operation named %s", operationName)
+	}
+
+	names := make([]string, 0, len(operations))
+	for name := range operations {
+		names = append(names, name)
+	}
+	// The document is kept the same for the same operations, as recorded requests are matched by it
+	slices.Sort(names)
+
+	named := make([]string, len(names))
+	for i, name := range names {
+		op, err := NameOperation(operations[name], name)
+		if err != nil {
+			return nil, fmt.Errorf("error naming operation %s: %v", name, err)
+		}
+
+		named[i] = op
+	}
+
+	req := NewRequest(strings.Join(named, "\n\n"), variables)
+	req.OperationName = operationName
+
+	return req, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_NameOperation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Query",
+			body: "query{me{id}}",
+			want: "query Me{me{id}}",
+		},
+		{
+			name: "Arguments",
+			body: "mutation ($id: ID!){deleteUser(id: $id)}",
+			want: "mutation Me ($id: ID!){deleteUser(id: $id)}",
+		},
+		{
+			name: "Shorthand",
+			body: "  { me { id } }",
+			want: "query Me { me { id } }",
+		},
+		{
+			name: "Directive",
+			body: "query @cached { me { id } }",
+			want: "query Me @cached { me { id } }",
+		},
+		{
+			name: "Fragment",
+			body: "fragment F on User { id }\nsubscription { userCreated { ...F } }",
+			want: "fragment F on User { id }\nsubscription Me { userCreated { ...F } }",
+		},
+		{
+			name: "ObjectDefault",
+			body: "query($f: Filter = {name: \"a\"}) { users(filter: $f) { id } }",
+			want: "query Me($f: Filter = {name: \"a\"}) { users(filter: $f) { id } }",
+		},
+		{
+			name:    "Named",
+			body:    "query Other { me { id } }",
+			wantErr: true,
+		},
+		{
+			name:    "NamedWithDirective",
+			body:    "query Other @cached { me { id } }",
+			wantErr: true,
+		},
+		{
+			name:    "NotAnOperation",
+			body:    "fragment F on User { id }",
+			wantErr: true,
+		},
+		{
+			name:    "SeveralOperations",
+			body:    "{ me { id } } { users { id } }",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated",
+			body:    "query { me { id }",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NameOperation(tt.body, "Me")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NameOperation(), got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NameOperation(), got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NewDocumentRequest(t *testing.T) {
+	operations := map[string]string{
+		"Users": "query{users{id}}",
+		"Me":    "query{me{id}}",
+	}
+
+	req, err := NewDocumentRequest(operations, "Users", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "query Me{me{id}}\n\nquery Users{users{id}}"
+	if req.Body != want || req.OperationName != "Users" {
+		t.Errorf("NewDocumentRequest(), got %q selecting %q, want %q selecting Users", req.Body, req.OperationName, want)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(req.Build(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["operationName"] != "Users" {
+		t.Errorf("Build(), got operationName %v, want Users", payload["operationName"])
+	}
+
+	if _, err := NewDocumentRequest(operations, "Other", nil); err == nil {
+		t.Errorf("NewDocumentRequest(), got no error for an operation not in the document")
+	}
+}
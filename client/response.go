@@ -17,7 +17,7 @@ type Response struct {
 type Error struct {
 	Message    string         `json:"message"`
 	Locations  []Location     `json:"locations"`
-	Path       []string       `json:"path"`
+	Path       []any          `json:"path"` // Strings for fields and numbers for list indices
 	Extensions map[string]any `json:"extensions"`
 }
 
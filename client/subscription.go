@@ -0,0 +1,287 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// The websocket subprotocols that subscriptions can be sent over
+type WSProtocol string
+
+const (
+	// https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+	GraphQLTransportWS WSProtocol = "graphql-transport-ws"
+	// The legacy protocol of subscriptions-transport-ws
+	// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+	GraphQLWS WSProtocol = "graphql-ws"
+)
+
+// The message types of both protocols
+const (
+	msgConnectionInit  = "connection_init"
+	msgConnectionAck   = "connection_ack"
+	msgConnectionError = "connection_error"
+	msgKeepAlive       = "ka"
+	msgPing            = "ping"
+	msgPong            = "pong"
+	msgSubscribe       = "subscribe"
+	msgStart           = "start"
+	msgNext            = "next"
+	msgData            = "data"
+	msgError           = "error"
+	msgComplete        = "complete"
+	msgStop            = "stop"
+)
+
+// The id of the single operation sent on each connection
+const subscriptionID = "1"
+
+var ErrSubscriptionTimeout = errors.New("connection not acknowledged before the timeout")
+
+type SubscriptionConfig struct {
+	Protocol WSProtocol
+
+	// The payload of the connection_init message, commonly used for authentication
+	InitPayload map[string]any
+
+	// How long to wait for each message before giving up
+	Timeout time.Duration
+}
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Subscribe starts the subscription and waits for its first message, after which the subscription is stopped.
+// A rejected connection or an error message are returned as a response containing the errors.
+// A subscription that is accepted but sends no event before the timeout is returned as a response without errors or data.
+// An ErrSubscriptionTimeout is returned if the connection is not acknowledged before the timeout
+func (c *Client) Subscribe(req *Request, cfg SubscriptionConfig) (*Response, error) {
+	if c.Cassette != nil {
+		i, err := c.Cassette.Replay(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if i.Error != "" {
+			return nil, &ReplayedError{Message: i.Error}
+		}
+
+		return parseResponse(i.StatusCode, []byte(i.Body))
+	}
+
+	start := time.Now()
+	resp, err := c.subscribe(req, cfg)
+
+	if c.Recorder != nil {
+		i := newInteraction(websocketURL(c.Endpoint), req, start)
+		if resp != nil {
+			b, _ := json.Marshal(resp)
+			i.StatusCode = resp.StatusCode
+			i.Body = string(b)
+		}
+		if err != nil {
+			i.Error = err.Error()
+		}
+
+		if err := c.Recorder.Record(i); err != nil {
+			return nil, fmt.Errorf("error recording subscription: %v", err)
+		}
+	}
+
+	return resp, err
+}
+
+func (c *Client) subscribe(req *Request, cfg SubscriptionConfig) (*Response, error) {
+	if cfg.Protocol != GraphQLTransportWS && cfg.Protocol != GraphQLWS {
+		return nil, fmt.Errorf("unsupported websocket protocol: %s", cfg.Protocol)
+	}
+
+	header := http.Header{}
+	for k, v := range req.Headers {
+		header.Add(k, v)
+	}
+
+	dialer := websocket.Dialer{
+		Subprotocols:     []string{string(cfg.Protocol)},
+		HandshakeTimeout: cfg.Timeout,
+	}
+
+	conn, httpResponse, err := dialer.Dial(websocketURL(c.Endpoint), header)
+	if err != nil {
+		if httpResponse != nil {
+			return &Response{
+				Errors:     []Error{{Message: fmt.Sprintf("websocket handshake failed: %s", httpResponse.Status)}},
+				StatusCode: httpResponse.StatusCode,
+			}, nil
+		}
+
+		return nil, fmt.Errorf("error connecting to websocket: %v", err)
+	}
+	defer conn.Close()
+
+	resp := &Response{
+		StatusCode: httpResponse.StatusCode,
+	}
+
+	initPayload, err := json.Marshal(cfg.InitPayload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling init payload: %v", err)
+	}
+
+	if err := conn.WriteJSON(wsMessage{Type: msgConnectionInit, Payload: initPayload}); err != nil {
+		return nil, fmt.Errorf("error initializing connection: %v", err)
+	}
+
+	acknowledged := false
+	for !acknowledged {
+		msg, err := readMessage(conn, cfg)
+		if err != nil {
+			return closedResponse(resp, err)
+		}
+
+		switch msg.Type {
+		case msgConnectionAck:
+			acknowledged = true
+		case msgConnectionError:
+			resp.Errors = parseErrorPayload(msg.Payload)
+			return resp, nil
+		}
+	}
+
+	startType, stopType := msgSubscribe, msgComplete
+	if cfg.Protocol == GraphQLWS {
+		startType, stopType = msgStart, msgStop
+	}
+
+	payload, err := json.Marshal(req.payload())
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling subscription: %v", err)
+	}
+
+	if err := conn.WriteJSON(wsMessage{ID: subscriptionID, Type: startType, Payload: payload}); err != nil {
+		return nil, fmt.Errorf("error starting subscription: %v", err)
+	}
+
+	for {
+		msg, err := readMessage(conn, cfg)
+		if isTimeout(err) {
+			// No event happened, but the subscription was not rejected either
+			conn.WriteJSON(wsMessage{ID: subscriptionID, Type: stopType})
+			return resp, nil
+		}
+		if err != nil {
+			return closedResponse(resp, err)
+		}
+
+		switch msg.Type {
+		case msgNext, msgData:
+			result, err := Parse(msg.Payload)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing subscription message: %v", err)
+			}
+			result.StatusCode = resp.StatusCode
+
+			conn.WriteJSON(wsMessage{ID: subscriptionID, Type: stopType})
+			return result, nil
+		case msgError:
+			resp.Errors = parseErrorPayload(msg.Payload)
+			return resp, nil
+		case msgComplete:
+			return resp, nil
+		}
+	}
+}
+
+// readMessage reads the next message that is not a keep alive, answering any pings
+func readMessage(conn *websocket.Conn, cfg SubscriptionConfig) (*wsMessage, error) {
+	for {
+		if cfg.Timeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(cfg.Timeout))
+		}
+
+		msg := &wsMessage{}
+		if err := conn.ReadJSON(msg); err != nil {
+			return nil, err
+		}
+
+		switch msg.Type {
+		case msgKeepAlive, msgPong:
+			continue
+		case msgPing:
+			if err := conn.WriteJSON(wsMessage{Type: msgPong}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return msg, nil
+	}
+}
+
+// closedResponse turns the reason for the connection ending into a response.
+// The server closing the connection, eg. with 4403: Forbidden, is returned as an error in the response
+// and a timeout as an ErrSubscriptionTimeout
+func closedResponse(resp *Response, err error) (*Response, error) {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		resp.Errors = []Error{{
+			Message: fmt.Sprintf("connection closed: %d %s", closeErr.Code, closeErr.Text),
+		}}
+		return resp, nil
+	}
+
+	if isTimeout(err) {
+		return nil, ErrSubscriptionTimeout
+	}
+
+	return nil, fmt.Errorf("error reading websocket message: %v", err)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// parseErrorPayload reads the errors of an error message.
+// The payload is a list of errors, a single error or an entire response depending on the protocol and server
+func parseErrorPayload(payload json.RawMessage) []Error {
+	var errs []Error
+	if err := json.Unmarshal(payload, &errs); err == nil {
+		return errs
+	}
+
+	var resp Response
+	if err := json.Unmarshal(payload, &resp); err == nil && len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+
+	var e Error
+	if err := json.Unmarshal(payload, &e); err == nil && e.Message != "" {
+		return []Error{e}
+	}
+
+	return []Error{{Message: string(payload)}}
+}
+
+// websocketURL converts a http(s) endpoint to the corresponding ws(s) endpoint
+func websocketURL(endpoint string) string {
+	if strings.HasPrefix(endpoint, "https://") {
+		return "wss://" + strings.TrimPrefix(endpoint, "https://")
+	}
+
+	if strings.HasPrefix(endpoint, "http://") {
+		return "ws://" + strings.TrimPrefix(endpoint, "http://")
+	}
+
+	return endpoint
+}
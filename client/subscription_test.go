@@ -0,0 +1,264 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newWebsocketServer starts a server running the handler on every websocket connection
+func newWebsocketServer(t *testing.T, handler func(t *testing.T, conn *websocket.Conn)) *httptest.Server {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{string(GraphQLTransportWS), string(GraphQLWS)},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("error upgrading connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		handler(t, conn)
+
+		// Keeps the connection open until the client is done with it
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// expectMessage reads the next message and checks that it is of the type
+func expectMessage(t *testing.T, conn *websocket.Conn, msgType string) wsMessage {
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Errorf("error reading %s message: %v", msgType, err)
+		return msg
+	}
+
+	if msg.Type != msgType {
+		t.Errorf("got %s message, want %s", msg.Type, msgType)
+	}
+
+	return msg
+}
+
+func Test_Subscribe(t *testing.T) {
+	data := json.RawMessage(`{"data":{"userCreated":{"id":"1"}}}`)
+
+	tests := []struct {
+		name       string
+		protocol   WSProtocol
+		headers    map[string]string
+		handler    func(t *testing.T, conn *websocket.Conn)
+		want       *Response
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:     "graphql-transport-ws",
+			protocol: GraphQLTransportWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				init := expectMessage(t, conn, msgConnectionInit)
+				if string(init.Payload) != `{"Authorization":"admin"}` {
+					t.Errorf("got init payload %s, want the authorization", init.Payload)
+				}
+
+				conn.WriteJSON(wsMessage{Type: msgConnectionAck})
+
+				sub := expectMessage(t, conn, msgSubscribe)
+				conn.WriteJSON(wsMessage{Type: msgPing})
+				expectMessage(t, conn, msgPong)
+
+				conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgNext, Payload: data})
+				expectMessage(t, conn, msgComplete)
+			},
+			want: &Response{
+				Data:       map[string]any{"userCreated": map[string]any{"id": "1"}},
+				StatusCode: http.StatusSwitchingProtocols,
+			},
+		},
+		{
+			name:     "graphql-ws",
+			protocol: GraphQLWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				expectMessage(t, conn, msgConnectionInit)
+				conn.WriteJSON(wsMessage{Type: msgConnectionAck})
+				conn.WriteJSON(wsMessage{Type: msgKeepAlive})
+
+				sub := expectMessage(t, conn, msgStart)
+				conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgData, Payload: data})
+				expectMessage(t, conn, msgStop)
+			},
+			want: &Response{
+				Data:       map[string]any{"userCreated": map[string]any{"id": "1"}},
+				StatusCode: http.StatusSwitchingProtocols,
+			},
+		},
+		{
+			name:     "error message",
+			protocol: GraphQLTransportWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				expectMessage(t, conn, msgConnectionInit)
+				conn.WriteJSON(wsMessage{Type: msgConnectionAck})
+
+				sub := expectMessage(t, conn, msgSubscribe)
+				conn.WriteJSON(wsMessage{ID: sub.ID, Type: msgError, Payload: json.RawMessage(`[{"message":"Forbidden"}]`)})
+			},
+			want: &Response{
+				Errors:     []Error{{Message: "Forbidden"}},
+				StatusCode: http.StatusSwitchingProtocols,
+			},
+		},
+		{
+			name:     "connection error",
+			protocol: GraphQLWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				expectMessage(t, conn, msgConnectionInit)
+				conn.WriteJSON(wsMessage{Type: msgConnectionError, Payload: json.RawMessage(`{"message":"Unauthorized"}`)})
+			},
+			want: &Response{
+				Errors:     []Error{{Message: "Unauthorized"}},
+				StatusCode: http.StatusSwitchingProtocols,
+			},
+		},
+		{
+			name:     "connection closed",
+			protocol: GraphQLTransportWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				expectMessage(t, conn, msgConnectionInit)
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(4403, "Forbidden"))
+			},
+			want: &Response{
+				Errors:     []Error{{Message: "connection closed: 4403 Forbidden"}},
+				StatusCode: http.StatusSwitchingProtocols,
+			},
+		},
+		{
+			name:     "handshake rejected",
+			protocol: GraphQLTransportWS,
+			headers:  map[string]string{},
+			want: &Response{
+				Errors:     []Error{{Message: "websocket handshake failed: 401 Unauthorized"}},
+				StatusCode: http.StatusUnauthorized,
+			},
+		},
+		{
+			name:     "no event before the timeout",
+			protocol: GraphQLTransportWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				expectMessage(t, conn, msgConnectionInit)
+				conn.WriteJSON(wsMessage{Type: msgConnectionAck})
+				expectMessage(t, conn, msgSubscribe)
+				expectMessage(t, conn, msgComplete)
+			},
+			want: &Response{
+				StatusCode: http.StatusSwitchingProtocols,
+			},
+		},
+		{
+			name:     "timeout",
+			protocol: GraphQLTransportWS,
+			handler: func(t *testing.T, conn *websocket.Conn) {
+				expectMessage(t, conn, msgConnectionInit)
+			},
+			wantErr: ErrSubscriptionTimeout,
+		},
+		{
+			name:       "unsupported protocol",
+			protocol:   WSProtocol("graphql-sse"),
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The connection outlives the subtest, so the handler must not be read through the loop variable
+			handler := tt.handler
+			server := newWebsocketServer(t, func(t *testing.T, conn *websocket.Conn) {
+				if handler != nil {
+					handler(t, conn)
+				}
+			})
+
+			headers := tt.headers
+			if headers == nil {
+				headers = map[string]string{"Authorization": "admin"}
+			}
+
+			req := NewRequest("subscription { userCreated { id } }", nil)
+			req.Headers = headers
+
+			c := New(server.URL)
+			got, err := c.Subscribe(req, SubscriptionConfig{
+				Protocol:    tt.protocol,
+				InitPayload: map[string]any{"Authorization": "admin"},
+				Timeout:     100 * time.Millisecond,
+			})
+
+			if tt.wantAnyErr || tt.wantErr != nil {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("Subscribe(), got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Subscribe(), got error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subscribe(), got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_WebsocketURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+	}{
+		{
+			name:     "http",
+			endpoint: "http://localhost:4000/graphql",
+			want:     "ws://localhost:4000/graphql",
+		},
+		{
+			name:     "https",
+			endpoint: "https://example.com/graphql",
+			want:     "wss://example.com/graphql",
+		},
+		{
+			name:     "websocket",
+			endpoint: "wss://example.com/graphql",
+			want:     "wss://example.com/graphql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := websocketURL(tt.endpoint); got != tt.want {
+				t.Errorf("websocketURL(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
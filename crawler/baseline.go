@@ -0,0 +1,171 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/utils"
+)
+
+// Baseline is the saved outcome of a crawl, used to find what changed in later crawls
+type Baseline struct {
+	Operations []BaselineEntry `json:"operations"`
+}
+
+type BaselineEntry struct {
+	Name     string             `json:"name"`
+	Type     client.RequestType `json:"type"`
+	Identity string             `json:"identity"`
+	Outcome  Outcome            `json:"outcome"`
+}
+
+// BaselineChange is an operation whose outcome differs from the baseline
+type BaselineChange struct {
+	Name     string             `json:"name"`
+	Type     client.RequestType `json:"type"`
+	Identity string             `json:"identity"`
+	Previous Outcome            `json:"previous"`
+	Current  Outcome            `json:"current"`
+}
+
+// BaselineDiff holds the differences between a baseline and a later crawl
+type BaselineDiff struct {
+	// Operations that were denied in the baseline and are now allowed
+	NewlyAllowed []BaselineChange `json:"newlyAllowed"`
+	// Operations that were allowed in the baseline and are now denied
+	NewlyDenied []BaselineChange `json:"newlyDenied"`
+
+	// Operations not in the baseline
+	Added []BaselineEntry `json:"added"`
+	// Operations in the baseline that were not performed
+	Removed []BaselineEntry `json:"removed"`
+}
+
+func NewBaseline(ops []CrawlOperation) Baseline {
+	b := Baseline{
+		Operations: make([]BaselineEntry, len(ops)),
+	}
+
+	for i, op := range ops {
+		b.Operations[i] = BaselineEntry{
+			Name:     op.Name,
+			Type:     op.Type,
+			Identity: op.Identity,
+			Outcome:  op.Outcome(),
+		}
+	}
+
+	return b
+}
+
+func LoadBaseline(file string) (Baseline, error) {
+	var b Baseline
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return b, fmt.Errorf("error reading baseline: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("error parsing baseline: %v", err)
+	}
+
+	return b, nil
+}
+
+func (b Baseline) Save(file string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling baseline: %v", err)
+	}
+
+	return utils.SaveToFile(file, data)
+}
+
+// baselineKey identifies an operation performed as an identity.
+// The type is part of it, as a query and a mutation may have the same name
+type baselineKey struct {
+	name     string
+	t        client.RequestType
+	identity string
+}
+
+func (e BaselineEntry) key() baselineKey {
+	return baselineKey{e.Name, e.Type, e.Identity}
+}
+
+// Compare finds the differences between the baseline and the operations of a later crawl
+func (b Baseline) Compare(ops []CrawlOperation) BaselineDiff {
+	var d BaselineDiff
+
+	previous := make(map[baselineKey]BaselineEntry)
+	for _, e := range b.Operations {
+		previous[e.key()] = e
+	}
+
+	current := NewBaseline(ops)
+	seen := make(map[baselineKey]bool)
+
+	for _, e := range current.Operations {
+		key := e.key()
+		seen[key] = true
+
+		prev, ok := previous[key]
+		if !ok {
+			d.Added = append(d.Added, e)
+			continue
+		}
+
+		change := BaselineChange{
+			Name:     e.Name,
+			Type:     e.Type,
+			Identity: e.Identity,
+			Previous: prev.Outcome,
+			Current:  e.Outcome,
+		}
+
+		if prev.Outcome == OutcomeDenied && e.Outcome == OutcomeAllowed {
+			d.NewlyAllowed = append(d.NewlyAllowed, change)
+		} else if prev.Outcome == OutcomeAllowed && e.Outcome == OutcomeDenied {
+			d.NewlyDenied = append(d.NewlyDenied, change)
+		}
+	}
+
+	for _, e := range b.Operations {
+		if !seen[e.key()] {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+
+	return d
+}
+
+// HasRegressions reports if any operation that was denied is now allowed
+func (d BaselineDiff) HasRegressions() bool {
+	return len(d.NewlyAllowed) > 0
+}
+
+func (d BaselineDiff) Print(w io.Writer) {
+	fmt.Fprintf(w, "Newly allowed: %d\n", len(d.NewlyAllowed))
+	for _, c := range d.NewlyAllowed {
+		fmt.Fprintf(w, "	%s \"%s\" as %s: %s -> %s\n", c.Type, c.Name, c.Identity, c.Previous, c.Current)
+	}
+
+	fmt.Fprintf(w, "Newly denied: %d\n", len(d.NewlyDenied))
+	for _, c := range d.NewlyDenied {
+		fmt.Fprintf(w, "	%s \"%s\" as %s: %s -> %s\n", c.Type, c.Name, c.Identity, c.Previous, c.Current)
+	}
+
+	fmt.Fprintf(w, "Added: %d\n", len(d.Added))
+	for _, e := range d.Added {
+		fmt.Fprintf(w, "	%s \"%s\" as %s: %s\n", e.Type, e.Name, e.Identity, e.Outcome)
+	}
+
+	fmt.Fprintf(w, "Removed: %d\n", len(d.Removed))
+	for _, e := range d.Removed {
+		fmt.Fprintf(w, "	%s \"%s\" as %s\n", e.Type, e.Name, e.Identity)
+	}
+}
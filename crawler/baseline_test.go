@@ -0,0 +1,61 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+func Test_BaselineCompare(t *testing.T) {
+	baseline := Baseline{
+		Operations: []BaselineEntry{
+			{Name: "users", Identity: "anonymous", Outcome: OutcomeDenied},
+			{Name: "me", Identity: "anonymous", Outcome: OutcomeAllowed},
+			{Name: "removed", Identity: "anonymous", Outcome: OutcomeDenied},
+		},
+	}
+
+	ops := []CrawlOperation{
+		{Name: "users", Identity: "anonymous"},
+		{Name: "me", Identity: "anonymous", Denied: true},
+		{Name: "added", Identity: "anonymous", Denied: true},
+	}
+
+	d := baseline.Compare(ops)
+
+	if len(d.NewlyAllowed) != 1 || d.NewlyAllowed[0].Name != "users" {
+		t.Errorf("NewlyAllowed = %v, want users", d.NewlyAllowed)
+	}
+	if len(d.NewlyDenied) != 1 || d.NewlyDenied[0].Name != "me" {
+		t.Errorf("NewlyDenied = %v, want me", d.NewlyDenied)
+	}
+	if len(d.Added) != 1 || d.Added[0].Name != "added" {
+		t.Errorf("Added = %v, want added", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "removed" {
+		t.Errorf("Removed = %v, want removed", d.Removed)
+	}
+	if !d.HasRegressions() {
+		t.Error("HasRegressions() = false, want true")
+	}
+}
+
+func Test_BaselineCompareSameName(t *testing.T) {
+	baseline := Baseline{
+		Operations: []BaselineEntry{
+			{Name: "user", Type: client.QueryRequest, Identity: "anonymous", Outcome: OutcomeAllowed},
+			{Name: "user", Type: client.MutationRequest, Identity: "anonymous", Outcome: OutcomeDenied},
+		},
+	}
+
+	ops := []CrawlOperation{
+		{Name: "user", Type: client.QueryRequest, Identity: "anonymous"},
+		{Name: "user", Type: client.MutationRequest, Identity: "anonymous", Denied: true},
+	}
+
+	d := baseline.Compare(ops)
+
+	if len(d.NewlyAllowed) != 0 || len(d.NewlyDenied) != 0 || len(d.Added) != 0 || len(d.Removed) != 0 {
+		t.Errorf("Compare() = %+v, want no differences", d)
+	}
+}
@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
+	"github.com/TheLeeeo/gql-test-suite/schema/manager"
+)
+
+// CrawlChanged performs the operations affected by the schema changes, as returned when polling
+func (c *Crawler) CrawlChanged(changes []diff.Change) ([]CrawlOperation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.IsReady() {
+		return nil, errors.New("no schema has been loaded")
+	}
+
+	return c.testOperations(c.affectedBy(changes)), nil
+}
+
+// affectedBy returns a filter accepting the operations affected by the changes.
+// An operation is affected if it was added or changed, or if any type it returns or takes as an argument changed
+func (c *Crawler) affectedBy(changes []diff.Change) func(kind client.RequestType, f schema.Field) bool {
+	roots := map[client.RequestType]string{
+		client.QueryRequest:        manager.RootTypeName(c.schema.QueryType, "Query"),
+		client.MutationRequest:     manager.RootTypeName(c.schema.MutationType, "Mutation"),
+		client.SubscriptionRequest: manager.RootTypeName(c.schema.SubscriptionType, "Subscription"),
+	}
+
+	changedFields := make(map[string]bool)
+	changedTypes := make(map[string]bool)
+
+	for _, change := range changes {
+		// A changed root type affects all of its operations
+		if strings.HasPrefix(change.Path, "schema.") {
+			return func(client.RequestType, schema.Field) bool { return true }
+		}
+
+		if strings.HasPrefix(change.Path, "@") {
+			continue
+		}
+
+		coordinate, _, _ := strings.Cut(change.Path, "(")
+		typeName, _, _ := strings.Cut(coordinate, ".")
+
+		changedFields[coordinate] = true
+		changedTypes[typeName] = true
+	}
+
+	// Changes to the fields of the root types are found per operation
+	for _, name := range roots {
+		delete(changedTypes, name)
+	}
+
+	return func(kind client.RequestType, f schema.Field) bool {
+		if changedFields[roots[kind]+"."+f.Name] {
+			return true
+		}
+
+		visited := make(map[string]bool)
+		if f.Type != nil && c.reaches(f.Type.GetBaseType().Name, changedTypes, visited) {
+			return true
+		}
+
+		for _, arg := range f.Args {
+			if arg.Type != nil && c.reaches(arg.Type.GetBaseType().Name, changedTypes, visited) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// reaches reports whether any of the targets is the type or is reachable through its fields, arguments, input fields or possible types
+func (c *Crawler) reaches(typeName string, targets map[string]bool, visited map[string]bool) bool {
+	if targets[typeName] {
+		return true
+	}
+
+	if visited[typeName] {
+		return false
+	}
+	visited[typeName] = true
+
+	t, ok := c.schemaManager.Types[typeName]
+	if !ok {
+		return false
+	}
+
+	for _, f := range t.Fields {
+		if f.Type != nil && c.reaches(f.Type.GetBaseType().Name, targets, visited) {
+			return true
+		}
+
+		for _, arg := range f.Args {
+			if arg.Type != nil && c.reaches(arg.Type.GetBaseType().Name, targets, visited) {
+				return true
+			}
+		}
+	}
+
+	for _, f := range t.InputFields {
+		if f.Type != nil && c.reaches(f.Type.GetBaseType().Name, targets, visited) {
+			return true
+		}
+	}
+
+	for _, p := range t.PossibleTypes {
+		if c.reaches(p.Name, targets, visited) {
+			return true
+		}
+	}
+
+	return false
+}
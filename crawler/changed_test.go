@@ -0,0 +1,109 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"golang.org/x/exp/slices"
+)
+
+const changedSchema = `
+type Query {
+  me: User
+  posts(filter: PostFilter): [Post]
+  version: String
+}
+
+type Mutation {
+  updateUser(id: ID!): User
+}
+
+type User {
+  id: ID!
+  profile: Profile
+}
+
+type Profile { bio: String }
+
+type Post { title: String }
+
+input PostFilter { author: ID }
+`
+
+func Test_AffectedBy(t *testing.T) {
+	s, err := sdl.Parse(changedSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Crawler{}
+	c.setSchema(s)
+
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{
+			name: "AddedOperation",
+			path: "Query.version",
+			want: []string{"version"},
+		},
+		{
+			name: "ChangedArgument",
+			path: "Mutation.updateUser(id:)",
+			want: []string{"updateUser"},
+		},
+		{
+			name: "NestedType",
+			path: "Profile.bio",
+			want: []string{"me", "updateUser"},
+		},
+		{
+			name: "InputType",
+			path: "PostFilter.author",
+			want: []string{"posts"},
+		},
+		{
+			name: "Directive",
+			path: "@auth",
+			want: nil,
+		},
+		{
+			name: "RootType",
+			path: "schema.query",
+			want: []string{"me", "posts", "updateUser", "version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := c.affectedBy([]diff.Change{{Path: tt.path}})
+
+			var got []string
+			for _, f := range c.schemaManager.Queries {
+				if filter(client.QueryRequest, f) {
+					got = append(got, f.Name)
+				}
+			}
+			for _, f := range c.schemaManager.Mutations {
+				if filter(client.MutationRequest, f) {
+					got = append(got, f.Name)
+				}
+			}
+
+			slices.Sort(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("affectedBy(%s), got %v, want %v", tt.path, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("affectedBy(%s), got %v, want %v", tt.path, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Package classifier decides the verdict of a graphql response, eg. if the request was denied, based on a set of rules
+package classifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"golang.org/x/exp/slices"
+)
+
+// A Verdict is the name of the conclusion drawn from a response.
+// Rules can produce any verdict, but the crawler gives special meaning to the predefined ones
+type Verdict string
+
+const (
+	VerdictAllowed Verdict = "ALLOWED"
+	VerdictDenied  Verdict = "DENIED"
+	VerdictFailed  Verdict = "FAILED"
+)
+
+// Rule produces its verdict for responses matching all of its conditions
+type Rule struct {
+	Name    string  `json:"name"`
+	Verdict Verdict `json:"verdict"`
+
+	// The http status codes to match
+	Status []int `json:"status,omitempty"`
+
+	// A regular expression to match the message of an error against
+	Message string `json:"message,omitempty"`
+
+	// Regular expressions to match the extensions of an error against, keyed by a dot separated path into the extensions.
+	// The path "*" matches the value of any top level extension
+	Extensions map[string]string `json:"extensions,omitempty"`
+
+	// A dot separated path into the data of the response that must be null or missing
+	DataNullAt string `json:"dataNullAt,omitempty"`
+}
+
+// Result is the outcome of classifying a response
+type Result struct {
+	Verdict Verdict
+	// The name of the rule that produced the verdict, empty if no rule matched
+	Rule string
+}
+
+type Classifier struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+
+	message    *regexp.Regexp
+	extensions map[string]*regexp.Regexp
+}
+
+// The rules file format
+type rulesFile struct {
+	// Evaluate the default rules after the rules in the file
+	IncludeDefaults bool   `json:"includeDefaults"`
+	Rules           []Rule `json:"rules"`
+}
+
+// DefaultRules classify the responses by looking for the errors returned by common graphql servers
+var DefaultRules = []Rule{
+	{
+		Name:    "fetch-failed",
+		Verdict: VerdictFailed,
+		Message: "HTTP fetch failed",
+	},
+	{
+		Name:    "unauthenticated",
+		Verdict: VerdictDenied,
+		Message: "(?i)unauthenticated",
+	},
+	{
+		Name:       "unauthenticated-extension",
+		Verdict:    VerdictDenied,
+		Extensions: map[string]string{"*": "(?i)unauthenticated"},
+	},
+	{
+		Name:    "permission-denied",
+		Verdict: VerdictDenied,
+		Message: "(?i)permissiondenied",
+	},
+}
+
+// New compiles the rules into a classifier. The rules are evaluated in order and the first matching rule decides the verdict
+func New(rules []Rule) (*Classifier, error) {
+	c := &Classifier{}
+
+	for _, r := range rules {
+		cr, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %s: %v", r.Name, err)
+		}
+
+		c.rules = append(c.rules, cr)
+	}
+
+	return c, nil
+}
+
+// Default creates a classifier using the default rules
+func Default() *Classifier {
+	c, err := New(DefaultRules)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Load reads the rules from a json file
+func Load(file string) (*Classifier, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rules file: %v", err)
+	}
+
+	var rf rulesFile
+	if err := json.Unmarshal(b, &rf); err != nil {
+		return nil, fmt.Errorf("error parsing rules file: %v", err)
+	}
+
+	rules := rf.Rules
+	if rf.IncludeDefaults {
+		rules = append(rules, DefaultRules...)
+	}
+
+	return New(rules)
+}
+
+func compileRule(r Rule) (compiledRule, error) {
+	cr := compiledRule{Rule: r}
+
+	if r.Verdict == "" {
+		return cr, errors.New("no verdict specified")
+	}
+
+	if len(r.Status) == 0 && r.Message == "" && len(r.Extensions) == 0 && r.DataNullAt == "" {
+		return cr, errors.New("no conditions specified")
+	}
+
+	if r.Message != "" {
+		re, err := regexp.Compile(r.Message)
+		if err != nil {
+			return cr, fmt.Errorf("invalid message pattern: %v", err)
+		}
+		cr.message = re
+	}
+
+	if len(r.Extensions) > 0 {
+		cr.extensions = make(map[string]*regexp.Regexp)
+		for path, pattern := range r.Extensions {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return cr, fmt.Errorf("invalid pattern for extension %s: %v", path, err)
+			}
+			cr.extensions[path] = re
+		}
+	}
+
+	return cr, nil
+}
+
+// Classify finds the verdict of the response. A nil response is classified as an empty response.
+// Responses not matching any rule are considered allowed
+func (c *Classifier) Classify(resp *client.Response) Result {
+	if resp == nil {
+		resp = &client.Response{}
+	}
+
+	for _, r := range c.rules {
+		if r.matches(resp) {
+			return Result{Verdict: r.Verdict, Rule: r.Name}
+		}
+	}
+
+	return Result{Verdict: VerdictAllowed}
+}
+
+// ClassifyError finds the verdict of a single error, only using the rules that match on errors alone.
+// Errors not matching any rule are considered allowed
+func (c *Classifier) ClassifyError(e client.Error) Result {
+	for _, r := range c.rules {
+		if len(r.Status) > 0 || r.DataNullAt != "" {
+			continue
+		}
+
+		if r.matchesError(e) {
+			return Result{Verdict: r.Verdict, Rule: r.Name}
+		}
+	}
+
+	return Result{Verdict: VerdictAllowed}
+}
+
+func (r *compiledRule) matches(resp *client.Response) bool {
+	if len(r.Status) > 0 && !slices.Contains(r.Status, resp.StatusCode) {
+		return false
+	}
+
+	if r.DataNullAt != "" && !isNullAt(resp.Data, strings.Split(r.DataNullAt, ".")) {
+		return false
+	}
+
+	if r.message == nil && r.extensions == nil {
+		return true
+	}
+
+	for _, e := range resp.Errors {
+		if r.matchesError(e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesError checks the error conditions of the rule against a single error
+func (r *compiledRule) matchesError(e client.Error) bool {
+	if r.message != nil && !r.message.MatchString(e.Message) {
+		return false
+	}
+
+	for path, re := range r.extensions {
+		if !extensionMatches(e.Extensions, path, re) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func extensionMatches(extensions map[string]any, path string, re *regexp.Regexp) bool {
+	if path == "*" {
+		for _, ext := range extensions {
+			if re.MatchString(stringValue(ext)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var value any = extensions
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		value, ok = m[key]
+		if !ok {
+			return false
+		}
+	}
+
+	return re.MatchString(stringValue(value))
+}
+
+// stringValue returns strings as they are and any other value as json
+func stringValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+func isNullAt(data map[string]any, path []string) bool {
+	var value any = data
+	for _, key := range path {
+		if value == nil {
+			return true
+		}
+
+		m, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		value = m[key]
+	}
+
+	return value == nil
+}
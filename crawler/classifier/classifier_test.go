@@ -0,0 +1,96 @@
+package classifier
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+func Test_Classify(t *testing.T) {
+	c, err := New(append([]Rule{
+		{
+			Name:    "http-status",
+			Verdict: VerdictDenied,
+			Status:  []int{401, 403},
+		},
+		{
+			Name:       "access-denied-code",
+			Verdict:    VerdictDenied,
+			Extensions: map[string]string{"code": "^ACCESS_DENIED$"},
+		},
+		{
+			Name:       "rate-limited",
+			Verdict:    "RATE_LIMITED",
+			Message:    "(?i)too many requests",
+			DataNullAt: "me",
+		},
+	}, DefaultRules...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		resp *client.Response
+		want Result
+	}{
+		{
+			name: "Nil",
+			resp: nil,
+			want: Result{Verdict: VerdictAllowed},
+		},
+		{
+			name: "Status",
+			resp: &client.Response{StatusCode: 403},
+			want: Result{Verdict: VerdictDenied, Rule: "http-status"},
+		},
+		{
+			name: "ExtensionPath",
+			resp: &client.Response{Errors: []client.Error{{Message: "no", Extensions: map[string]any{"code": "ACCESS_DENIED"}}}},
+			want: Result{Verdict: VerdictDenied, Rule: "access-denied-code"},
+		},
+		{
+			name: "CustomVerdict",
+			resp: &client.Response{Errors: []client.Error{{Message: "Too many requests"}}, Data: map[string]any{"me": nil}},
+			want: Result{Verdict: "RATE_LIMITED", Rule: "rate-limited"},
+		},
+		{
+			name: "DataNotNull",
+			resp: &client.Response{Errors: []client.Error{{Message: "Too many requests"}}, Data: map[string]any{"me": map[string]any{}}},
+			want: Result{Verdict: VerdictAllowed},
+		},
+		{
+			name: "DefaultMessage",
+			resp: &client.Response{Errors: []client.Error{{Message: "PermissionDenied: admins only"}}},
+			want: Result{Verdict: VerdictDenied, Rule: "permission-denied"},
+		},
+		{
+			name: "DefaultExtension",
+			resp: &client.Response{Errors: []client.Error{{Message: "no", Extensions: map[string]any{"reason": map[string]any{"code": "UNAUTHENTICATED"}}}}},
+			want: Result{Verdict: VerdictDenied, Rule: "unauthenticated-extension"},
+		},
+		{
+			name: "FetchFailed",
+			resp: &client.Response{Errors: []client.Error{{Message: "HTTP fetch failed from 'users'"}}},
+			want: Result{Verdict: VerdictFailed, Rule: "fetch-failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Classify(tt.resp); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	if _, err := New([]Rule{{Name: "empty", Verdict: VerdictDenied}}); err == nil {
+		t.Error("New() expected an error for a rule without conditions")
+	}
+
+	if _, err := New([]Rule{{Name: "invalid", Verdict: VerdictDenied, Message: "("}}); err == nil {
+		t.Error("New() expected an error for an invalid pattern")
+	}
+}
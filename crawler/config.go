@@ -1,10 +1,85 @@
 package crawler
 
-import "github.com/TheLeeeo/gql-test-suite/introspection"
+import (
+	"time"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
+	"github.com/TheLeeeo/gql-test-suite/crawler/selector"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema/manager"
+)
 
 type Config struct {
 	ClientConfig introspection.Config
 
-	// Operations to ignore
-	Ignore []string
+	ManagerConfig manager.Config
+
+	// Load the schema from a saved introspection result (.json), an SDL file or a directory of SDL files
+	// instead of introspecting the target
+	SchemaFile string
+
+	// Recover the schema from the validation errors of the target if introspection fails
+	RecoverSchema bool
+
+	// The names to probe when recovering the schema, introspection.DefaultWordlist if empty
+	Wordlist []string
+
+	// Selectors of the operations to crawl, every operation is crawled if empty.
+	// See the selector package for the syntax
+	Include selector.Set
+
+	// Selectors of the operations to ignore
+	Ignore selector.Set
+
+	// Which mutations to perform, MutationsSafe if empty
+	Mutations MutationMode
+
+	// Name patterns of the mutations that are not performed in the safe mode, DefaultDestructivePatterns if empty
+	DestructivePatterns []string
+
+	// Host patterns of the targets that mutations are never performed against, regardless of the mutation mode
+	ProductionHosts []string
+
+	// Who operations are supposed to be allowed for, keyed by operation name,
+	// or by type and name like "mutation.user" when a query and a mutation have the same name
+	Expectations map[string]Expectation
+
+	// The identities to perform every operation as, sending the headers of the ClientConfig along with their own.
+	// Identities without headers or an init payload of their own are anonymous and send no headers at all.
+	// If empty, a single identity using the headers of the ClientConfig is used
+	Identities []Identity
+
+	// The number of operations to perform at the same time
+	Concurrency int
+
+	// The number of queries to send per array batched request, queries are sent one at a time if less than 2
+	BatchSize int
+
+	// How long to wait for the response of a request, 0 means no limit
+	RequestTimeout time.Duration
+
+	// The maximum number of requests to make per second, 0 means no limit
+	RateLimit float64
+
+	// Decides the verdict of responses, the default rules are used if nil
+	Classifier *classifier.Classifier
+
+	// Literal values to use for custom scalars, keyed by scalar name
+	Scalars map[string]any
+
+	// Records every request and its response if set
+	Recorder *client.Recorder
+
+	// Serves the responses of a recorded crawl instead of sending requests if set
+	Cassette *client.Cassette
+
+	// The websocket protocol to crawl subscriptions with. Subscriptions are not crawled if empty
+	SubscriptionProtocol client.WSProtocol
+
+	// The websocket endpoint for subscriptions, defaults to the target url
+	SubscriptionUrl string
+
+	// How long to wait for each message of a subscription
+	SubscriptionTimeout time.Duration
 }
@@ -2,29 +2,60 @@ package crawler
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
 	"github.com/fatih/color"
 )
 
 type CrawlOperation struct {
 	// The name of the operation
 	Name string `json:"name"`
+	// The kind of operation
+	Type client.RequestType `json:"type"`
+	// The name of the identity the operation was performed as
+	Identity string `json:"identity"`
 	// Thes request made
 	Request client.Request `json:"request"`
+	// Did the identity send credentials
+	Authenticated bool `json:"authenticated"`
+	// Who the operation is supposed to be allowed for
+	Expectation Expectation `json:"expectation,omitempty"`
+
+	// The verdict of the classifier and the name of the rule that produced it
+	Verdict classifier.Verdict `json:"verdict"`
+	Rule    string             `json:"rule,omitempty"`
 
 	// Was the operation considered denied
 	Denied bool `json:"success"`
 	// Failed to perform the operation
 	Failed bool `json:"failed"`
+	// The operation was never performed
+	Skipped bool `json:"skipped"`
+	// Why the operation was skipped
+	SkipReason string `json:"skipReason,omitempty"`
 
 	// The response of the operation
 	Response string `json:"response"`
+	// The http status code of the response
+	StatusCode int `json:"statusCode"`
 
 	// The error message if the operation failed
 	Error error `json:"error"`
 }
 
+// Outcome is the summarized result of performing an operation
+type Outcome string
+
+const (
+	OutcomeAllowed Outcome = "ALLOWED"
+	OutcomeDenied  Outcome = "DENIED"
+	OutcomeFailed  Outcome = "FAILED"
+	OutcomeSkipped Outcome = "SKIPPED"
+)
+
 func NewOperation(name string, req client.Request) CrawlOperation {
 	resp := CrawlOperation{
 		Name:    name,
@@ -34,27 +65,81 @@ func NewOperation(name string, req client.Request) CrawlOperation {
 	return resp
 }
 
-func (o *CrawlOperation) SetResponse(resp []byte) {
+// SetResponse stores the response and the verdict it was classified as
+func (o *CrawlOperation) SetResponse(resp []byte, statusCode int, result classifier.Result) {
 	o.Response = string(resp)
+	o.StatusCode = statusCode
+
+	o.Verdict = result.Verdict
+	o.Rule = result.Rule
+
+	o.Denied = result.Verdict == classifier.VerdictDenied
+	o.Failed = result.Verdict == classifier.VerdictFailed
+}
+
+// Skip marks the operation as not performed
+func (o *CrawlOperation) Skip(reason string) {
+	o.Skipped = true
+	o.SkipReason = reason
+}
+
+func (o *CrawlOperation) Outcome() Outcome {
+	if o.Skipped {
+		return OutcomeSkipped
+	}
+
+	if o.Failed || o.Error != nil {
+		return OutcomeFailed
+	}
+
+	if o.Denied {
+		return OutcomeDenied
+	}
 
-	o.Denied = is403Error(resp) || is401Error(resp)
-	o.Failed = isFetchFailed(resp)
+	// Custom verdicts from the classifier rules are kept as they are
+	if o.Verdict != "" && o.Verdict != classifier.VerdictAllowed {
+		return Outcome(o.Verdict)
+	}
+
+	return OutcomeAllowed
 }
 
 func (o *CrawlOperation) PrintResult() {
+	o.WriteResult(os.Stdout)
+}
+
+// WriteResult writes the colored, human readable result of the operation
+func (o *CrawlOperation) WriteResult(w io.Writer) {
 	var resultString string
 
-	if o.Failed {
+	switch o.Outcome() {
+	case OutcomeSkipped:
+		resultString = color.BlueString("SKIPPED: %s", o.SkipReason)
+	case OutcomeFailed:
 		resultString = color.YellowString("FAILED TO FETCH")
-	} else if o.Denied {
+	case OutcomeDenied:
 		resultString = color.GreenString("DENIED")
-	} else {
+	case OutcomeAllowed:
 		resultString = color.RedString("ALLOWED")
+	default:
+		resultString = color.YellowString(string(o.Outcome()))
+	}
+
+	// Operations with an expectation are colored by whether they met it
+	switch o.ExpectationResult() {
+	case ExpectationPass:
+		resultString = color.GreenString("%s (expected %s)", o.Outcome(), o.Expectation)
+	case ExpectationFail:
+		resultString = color.RedString("%s (expected %s)", o.Outcome(), o.Expectation)
 	}
 
-	fmt.Printf("\"%s\": %s\n", o.Name, resultString)
-	// Got allowed
-	if !o.Failed && !o.Denied {
-		fmt.Println("	Response: ", o.Response)
+	if o.Identity != "" && o.Identity != DefaultIdentityName {
+		fmt.Fprintf(w, "\"%s\" as %s: %s\n", o.Name, o.Identity, resultString)
+	} else {
+		fmt.Fprintf(w, "\"%s\": %s\n", o.Name, resultString)
+	}
+	// Got allowed when not expected to be
+	if o.Outcome() == OutcomeAllowed && o.ExpectationResult() != ExpectationPass {
+		fmt.Fprintln(w, "	Response: ", o.Response)
 	}
 }
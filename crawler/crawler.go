@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"time"
+	"sync"
 
 	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
+	"github.com/TheLeeeo/gql-test-suite/crawler/selector"
 	"github.com/TheLeeeo/gql-test-suite/introspection"
 	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
 	"github.com/TheLeeeo/gql-test-suite/schema/manager"
+	"github.com/TheLeeeo/gql-test-suite/schema/scalars"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
@@ -21,26 +26,77 @@ type Crawler struct {
 
 	gqlClient *client.Client
 
+	// The client used for subscriptions
+	wsClient *client.Client
+
+	// Held while crawling and while changing the schema or config, so that a crawl uses the same schema and config throughout.
+	// Crawls are performed one at a time as a result
+	mu sync.Mutex
+
+	// The schema being crawled, kept to find what changed when polling
+	schema        *schema.Schema
 	schemaManager *manager.Manager
+
+	// The leak found when recovering the schema, if it was recovered
+	suggestionLeak *introspection.SuggestionLeak
+
+	// Generates the values of scalar arguments
+	scalars *scalars.Registry
+
+	// Decides the verdict of responses
+	classifier *classifier.Classifier
 }
 
-var defaultUnsupportedQueries = []string{
-	"_entities",
-	"_service",
+var defaultUnsupportedQueries = selector.Set{
+	selector.MustParse("_entities"),
+	selector.MustParse("_service"),
 }
 
 // New creates a new crawler
 func New(cfg Config) *Crawler {
 	cfg.Ignore = append(cfg.Ignore, defaultUnsupportedQueries...)
 
+	if len(cfg.Identities) == 0 {
+		cfg.Identities = []Identity{
+			{
+				Name:    DefaultIdentityName,
+				Headers: cfg.ClientConfig.Headers,
+			},
+		}
+	}
+
 	ic := introspection.New(cfg.ClientConfig)
 
 	gqlC := client.New(cfg.ClientConfig.TargetUrl)
+	gqlC.Timeout = cfg.RequestTimeout
+
+	wsC := gqlC
+	if cfg.SubscriptionUrl != "" {
+		wsC = client.New(cfg.SubscriptionUrl)
+	}
+
+	for _, c := range []*client.Client{ic.Client(), gqlC, wsC} {
+		c.Recorder = cfg.Recorder
+		c.Cassette = cfg.Cassette
+	}
+
+	cl := cfg.Classifier
+	if cl == nil {
+		cl = classifier.Default()
+	}
+
+	sr := scalars.New()
+	for name, value := range cfg.Scalars {
+		sr.RegisterValue(name, value)
+	}
 
 	return &Crawler{
 		intrClient: ic,
 		gqlClient:  gqlC,
+		wsClient:   wsC,
 		cfg:        cfg,
+		scalars:    sr,
+		classifier: cl,
 	}
 }
 
@@ -48,43 +104,201 @@ func (c *Crawler) IsReady() bool {
 	return c.schemaManager != nil
 }
 
+// SetTargetURL changes the endpoint that the schema is introspected from and that operations are sent to
 func (c *Crawler) SetTargetURL(targetURL string) error {
-	return c.intrClient.SetTargetURL(targetURL)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.intrClient.SetTargetURL(targetURL); err != nil {
+		return err
+	}
+	c.gqlClient.Endpoint = targetURL
+
+	return nil
 }
 
 func (c *Crawler) GetTargetURL() string {
 	return c.intrClient.Cfg.TargetUrl
 }
 
-func (c *Crawler) SetIgnore(ignore []string) {
-	c.cfg.Ignore = append(ignore, defaultUnsupportedQueries...)
+// SetIgnore replaces the selectors of the operations to ignore, failing if any of them is invalid
+func (c *Crawler) SetIgnore(ignore []string) error {
+	set, err := selector.ParseSet(ignore)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg.Ignore = append(set, defaultUnsupportedQueries...)
+
+	return nil
 }
 
 func (c *Crawler) GetIgnore() []string {
-	return c.cfg.Ignore
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cfg.Ignore.Strings()
+}
+
+func (c *Crawler) SetExpectations(expectations map[string]Expectation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg.Expectations = expectations
+}
+
+func (c *Crawler) GetExpectations() map[string]Expectation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cfg.Expectations
+}
+
+// GetIdentities returns the names of the identities that operations are performed as
+func (c *Crawler) GetIdentities() []string {
+	names := make([]string, len(c.cfg.Identities))
+	for i, id := range c.cfg.Identities {
+		names[i] = id.Name
+	}
+
+	return names
 }
 
-func (c *Crawler) StartPolling() {
+// setSchema replaces the schema being crawled, the caller must hold the lock of the crawler if it may be crawling
+func (c *Crawler) setSchema(s *schema.Schema) {
+	c.schema = s
+	c.schemaManager = manager.New(s, c.cfg.ManagerConfig)
+}
+
+// logSchemaChanges logs a summary of the changes found when polling, followed by every change
+func logSchemaChanges(changes []diff.Change) {
+	if len(changes) == 0 {
+		log.Println("The schema has not changed")
+		return
+	}
+
+	log.Println("The schema has changed: ", diff.Summary(changes))
+	for _, change := range changes {
+		log.Printf("\t%s %s", change.Severity, change.Message)
+	}
+}
+
+// fetchSchema loads the schema from the configured file, or introspects the target if there is none.
+// If introspection fails the schema is recovered from suggestions when enabled
+func (c *Crawler) fetchSchema() (*schema.Schema, error) {
+	if c.cfg.SchemaFile != "" {
+		return introspection.LoadSchema(c.cfg.SchemaFile)
+	}
+
+	s, err := c.intrClient.FetchSchema()
+	if err == nil || !c.cfg.RecoverSchema {
+		return s, err
+	}
+
+	log.Printf("Introspection failed (%v), recovering the schema from suggestions", err)
+
+	rec, err := c.intrClient.RecoverSchema(introspection.RecoveryConfig{
+		Wordlist:  c.cfg.Wordlist,
+		RateLimit: c.cfg.RateLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error recovering schema: %v", err)
+	}
+
+	log.Printf("Recovered %d types with %d probes", len(rec.Schema.Types), rec.Probes)
+
+	c.suggestionLeak = rec.Leak
+
+	return rec.Schema, nil
+}
+
+// GetSuggestionLeak returns the leak found if the schema was recovered from suggestions
+func (c *Crawler) GetSuggestionLeak() *introspection.SuggestionLeak {
+	return c.suggestionLeak
+}
+
+// StartPolling polls the target for changes to the schema, calling onChange, if not nil, with the changes found
+func (c *Crawler) StartPolling(onChange func(changes []diff.Change)) {
+	if c.cfg.SchemaFile != "" {
+		log.Println("The schema is loaded from a file, skipping polling")
+		return
+	}
+
 	c.intrClient.StartPolling(func(s *schema.Schema) {
-		c.schemaManager = manager.New(s)
+		c.mu.Lock()
+		var changes []diff.Change
+		if c.schema != nil {
+			changes = diff.Compare(c.schema, s)
+			logSchemaChanges(changes)
+		}
+
+		c.setSchema(s)
+		c.mu.Unlock()
+
+		if len(changes) > 0 && onChange != nil {
+			onChange(changes)
+		}
 	})
 }
 
 func (c *Crawler) Crawl() ([]CrawlOperation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if !c.IsReady() {
-		s, err := c.intrClient.FetchSchema()
+		s, err := c.fetchSchema()
 		if err != nil {
 			return nil, err
 		}
 
-		c.schemaManager = manager.New(s)
+		c.setSchema(s)
 	}
 
 	return c.testAllOperations(), nil
 }
 
 func (c *Crawler) Do(op *CrawlOperation) error {
-	resp, err := c.gqlClient.Execute(&op.Request)
+	if op.Type == client.MutationRequest {
+		if host, ok := c.productionHost(); ok {
+			return fmt.Errorf("refusing to perform the mutation %s against the production host %s", op.Name, host)
+		}
+	}
+
+	var resp *client.Response
+	var err error
+	if op.Type == client.SubscriptionRequest {
+		resp, err = c.wsClient.Subscribe(&op.Request, c.subscriptionConfig(op.Identity))
+	} else {
+		resp, err = c.gqlClient.Execute(&op.Request)
+	}
+
+	return c.setResponse(op, resp, err)
+}
+
+// doBatch performs the operations as one array batch, they must be queries or mutations with the same headers
+func (c *Crawler) doBatch(ops []*CrawlOperation) error {
+	reqs := make([]*client.Request, len(ops))
+	for i, op := range ops {
+		reqs[i] = &op.Request
+	}
+
+	resps, err := c.gqlClient.ExecuteBatch(reqs)
+	if err != nil {
+		return err
+	}
+
+	for i, op := range ops {
+		c.setResponse(op, resps[i], nil)
+	}
+
+	return nil
+}
+
+// setResponse sets the result of performing the operation, returning the error of the request
+func (c *Crawler) setResponse(op *CrawlOperation, resp *client.Response, err error) error {
 	if err != nil {
 		op.Error = err
 	}
@@ -94,146 +308,300 @@ func (c *Crawler) Do(op *CrawlOperation) error {
 		return err
 	}
 
-	op.SetResponse(r)
+	var statusCode int
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	result := c.classifier.Classify(resp)
+	// Nothing was received, eg. as the target could not be reached, so there is nothing to classify
+	if op.Error != nil && resp == nil {
+		result = classifier.Result{Verdict: classifier.VerdictFailed}
+	}
+
+	op.SetResponse(r, statusCode, result)
 
-	return err
+	return op.Error
 }
 
-func (c *Crawler) TestQuery(queryName string) *CrawlOperation {
-	var query *schema.Field
-	for _, q := range c.schemaManager.Queries {
-		if q.Name == queryName {
-			query = &q
+// subscriptionConfig creates the config for subscribing as the identity.
+// The headers of the identity are used as the init payload unless the identity has its own
+func (c *Crawler) subscriptionConfig(identity string) client.SubscriptionConfig {
+	cfg := client.SubscriptionConfig{
+		Protocol: c.cfg.SubscriptionProtocol,
+		Timeout:  c.cfg.SubscriptionTimeout,
+	}
+
+	for _, id := range c.cfg.Identities {
+		if id.Name != identity {
+			continue
+		}
+
+		if id.InitPayload != nil {
+			cfg.InitPayload = id.InitPayload
 			break
 		}
-	}
-	if query == nil {
-		log.Println("Query not found")
-		return nil
-	}
 
-	vars := c.GenerateMinimalTestDataForRequest(query)
-	r := c.schemaManager.Build(*query, client.QueryRequest)
+		headers := id.RequestHeaders(c.cfg.ClientConfig.Headers)
+		cfg.InitPayload = make(map[string]any, len(headers))
+		for k, v := range headers {
+			cfg.InitPayload[k] = v
+		}
+		break
+	}
 
-	request := client.NewRequest(r, vars)
-	operation := NewOperation(queryName, *request)
+	return cfg
+}
 
-	c.Do(&operation)
+// TestQuery performs the query as every identity
+func (c *Crawler) TestQuery(queryName string) ([]CrawlOperation, error) {
+	return c.testField(client.QueryRequest, queryName)
+}
 
-	return &operation
+// TestMutation performs the mutation as every identity
+func (c *Crawler) TestMutation(mutationName string) ([]CrawlOperation, error) {
+	return c.testField(client.MutationRequest, mutationName)
 }
 
-func (c *Crawler) TestMutation(mutationName string) *CrawlOperation {
-	var mutation *schema.Field
-	for _, m := range c.schemaManager.Mutations {
-		if m.Name == mutationName {
-			mutation = &m
-			break
+// testField performs the query or mutation with the name as every identity, loading the schema if needed
+func (c *Crawler) testField(t client.RequestType, name string) ([]CrawlOperation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.IsReady() {
+		s, err := c.fetchSchema()
+		if err != nil {
+			return nil, err
 		}
-	}
-	if mutation == nil {
-		log.Println("Mutation not found")
-		return nil
+
+		c.setSchema(s)
 	}
 
-	vars := c.GenerateMinimalTestDataForRequest(mutation)
-	r := c.schemaManager.Build(*mutation, client.MutationRequest)
-	req := client.NewRequest(r, vars)
+	fields := c.schemaManager.Queries
+	if t == client.MutationRequest {
+		fields = c.schemaManager.Mutations
+	}
 
-	operation := NewOperation(mutationName, *req)
+	f, ok := fields[name]
+	if !ok {
+		return nil, fmt.Errorf("the schema has no %s %s", t, name)
+	}
 
-	c.Do(&operation)
+	ops := c.newOperations(f, t)
+	c.doAll(ops)
 
-	return &operation
+	return ops, nil
 }
 
 func (c *Crawler) testAllOperations() []CrawlOperation {
+	return c.testOperations(nil)
+}
+
+// testOperations performs the selected operations accepted by the filter, or every selected operation if the filter is nil
+func (c *Crawler) testOperations(filter func(kind client.RequestType, f schema.Field) bool) []CrawlOperation {
 	var allOperations []CrawlOperation
 
-	for _, q := range c.schemaManager.Queries {
-		if slices.Contains(c.cfg.Ignore, q.Name) {
+	selected := func(kind client.RequestType, f schema.Field) bool {
+		return c.selected(kind, f) && (filter == nil || filter(kind, f))
+	}
+
+	for _, name := range sortedNames(c.schemaManager.Queries) {
+		if !selected(client.QueryRequest, c.schemaManager.Queries[name]) {
 			continue
 		}
 
-		vars := c.GenerateMinimalTestDataForRequest(&q)
-		r := c.schemaManager.Build(q, client.QueryRequest)
+		allOperations = append(allOperations, c.newOperations(c.schemaManager.Queries[name], client.QueryRequest)...)
+	}
 
-		req := client.NewRequest(r, vars)
-		crawlResp := NewOperation(q.Name, *req)
+	for _, name := range sortedNames(c.schemaManager.Mutations) {
+		if !selected(client.MutationRequest, c.schemaManager.Mutations[name]) {
+			continue
+		}
 
-		// fmt.Printf("%+v\n", req)
+		allOperations = append(allOperations, c.newOperations(c.schemaManager.Mutations[name], client.MutationRequest)...)
+	}
 
-		c.Do(&crawlResp)
+	if c.cfg.SubscriptionProtocol != "" {
+		for _, name := range sortedNames(c.schemaManager.Subscriptions) {
+			if !selected(client.SubscriptionRequest, c.schemaManager.Subscriptions[name]) {
+				continue
+			}
 
-		allOperations = append(allOperations, crawlResp)
+			allOperations = append(allOperations, c.newOperations(c.schemaManager.Subscriptions[name], client.SubscriptionRequest)...)
+		}
 	}
 
-	for _, m := range c.schemaManager.Mutations {
-		if slices.Contains(c.cfg.Ignore, m.Name) {
-			continue
-		}
-		vars := c.GenerateMinimalTestDataForRequest(&m)
-		r := c.schemaManager.Build(m, client.MutationRequest)
-		req := client.NewRequest(r, vars)
-		crawlResp := NewOperation(m.Name, *req)
+	c.doAll(allOperations)
 
-		c.Do(&crawlResp)
+	return allOperations
+}
 
-		allOperations = append(allOperations, crawlResp)
+// selected reports whether the operation is included and not ignored
+func (c *Crawler) selected(kind client.RequestType, f schema.Field) bool {
+	if len(c.cfg.Include) > 0 && !c.cfg.Include.Matches(kind, f) {
+		return false
 	}
 
-	return allOperations
+	return !c.cfg.Ignore.Matches(kind, f)
 }
 
-func (c *Crawler) GenerateMinimalTestDataForRequest(f *schema.Field) map[string]any {
-	if len(f.Args) == 0 {
-		return nil
+// newOperations builds the request for the field and creates one operation for it per configured identity.
+// The operations are marked as skipped if they are mutations that must not be performed, or if no test data could be generated for the request
+func (c *Crawler) newOperations(f schema.Field, t client.RequestType) []CrawlOperation {
+	vars, err := c.GenerateMinimalTestDataForRequest(&f)
+	r := c.schemaManager.Build(f, t)
+	req := client.NewRequest(r, vars)
+
+	var reason string
+	if t == client.MutationRequest {
+		reason = c.mutationSkipReason(f.Name)
 	}
 
-	vars := make(map[string]any)
+	ops := make([]CrawlOperation, 0, len(c.cfg.Identities))
+
+	for _, id := range c.cfg.Identities {
+		req.Headers = id.RequestHeaders(c.cfg.ClientConfig.Headers)
 
-	arg := f.Args[0]
+		op := NewOperation(f.Name, *req)
+		op.Identity = id.Name
+		op.Type = t
+		op.Authenticated = !id.Anonymous()
+		op.Expectation = expectationFor(c.cfg.Expectations, t, f.Name)
 
-	if arg.Type.Kind != schema.NonNullTypeKind {
-		//Optional args :)
-		return nil
+		if reason != "" {
+			op.Skip(reason)
+		} else if err != nil {
+			op.Skip(err.Error())
+		}
+
+		ops = append(ops, op)
 	}
 
-	baseType := arg.Type.GetBaseType()
+	return ops
+}
 
-	var value any
-	switch baseType.Kind {
-	case schema.EnumTypeKind:
-		value = f.Type.EnumValues
-	case schema.ScalarTypeKind:
-		if baseType.Name == "Boolean" {
-			value = true
-		} else if baseType.Name == "String" {
-			value = "0"
-		} else if baseType.Name == "Int" {
-			value = 0
-		} else if baseType.Name == "Float" {
-			value = 0.0
-		} else if baseType.Name == "ID" {
-			value = "0"
-		} else if baseType.Name == "Time" {
-			value = time.Now()
-		} else {
-			panic(fmt.Sprintf("Unhandled scalar type %s", baseType.Name))
+// doAll performs the operations in place using the configured number of workers,
+// while keeping within the configured rate limit
+func (c *Crawler) doAll(ops []CrawlOperation) {
+	workers := c.cfg.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	l := newRateLimiter(c.cfg.RateLimit)
+	defer l.Stop()
+
+	units := make(chan []int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for unit := range units {
+				l.Wait()
+
+				if len(unit) == 1 {
+					c.Do(&ops[unit[0]])
+					continue
+				}
+
+				batch := make([]*CrawlOperation, len(unit))
+				for i, index := range unit {
+					batch[i] = &ops[index]
+				}
+
+				if err := c.doBatch(batch); err != nil {
+					log.Printf("A batch of %d operations failed, performing them one at a time: %v", len(batch), err)
+
+					for _, op := range batch {
+						l.Wait()
+						c.Do(op)
+					}
+				}
+			}
+		}()
+	}
+
+	for _, unit := range c.batches(ops) {
+		units <- unit
+	}
+	close(units)
+
+	wg.Wait()
+}
+
+// batches groups the indexes of the operations that are performed together, leaving out skipped operations.
+// Queries performed as the same identity are batched if a batch size is configured, other operations are performed on their own
+func (c *Crawler) batches(ops []CrawlOperation) [][]int {
+	var units [][]int
+
+	// The queries waiting for their batch to fill up, per identity
+	pending := make(map[string][]int)
+	var identities []string
+
+	for i, op := range ops {
+		if op.Skipped {
+			continue
 		}
-	case schema.InputObjectTypeKind:
-		completeBaseType := c.schemaManager.Types[baseType.Name]
-		value = c.GenerateMinimalTestDataForType(&completeBaseType)
-	default:
-		panic(fmt.Sprintf("Unimplemented variable kind %s", f.Type.Kind))
+
+		if c.cfg.BatchSize < 2 || op.Type != client.QueryRequest {
+			units = append(units, []int{i})
+			continue
+		}
+
+		if _, ok := pending[op.Identity]; !ok {
+			identities = append(identities, op.Identity)
+		}
+
+		pending[op.Identity] = append(pending[op.Identity], i)
+		if len(pending[op.Identity]) == c.cfg.BatchSize {
+			units = append(units, pending[op.Identity])
+			pending[op.Identity] = nil
+		}
+	}
+
+	for _, id := range identities {
+		if len(pending[id]) > 0 {
+			units = append(units, pending[id])
+		}
+	}
+
+	return units
+}
+
+// sortedNames returns the names of the fields in alphabetical order
+func sortedNames(fields map[string]schema.Field) []string {
+	names := maps.Keys(fields)
+	slices.Sort(names)
+
+	return names
+}
+
+// GenerateMinimalTestDataForRequest generates a value for every argument that is sent with the request for the field
+func (c *Crawler) GenerateMinimalTestDataForRequest(f *schema.Field) (map[string]any, error) {
+	args := c.schemaManager.RequestArgs(*f)
+	if len(args) == 0 {
+		return nil, nil
 	}
 
-	vars[arg.Name] = value
+	vars := make(map[string]any)
+
+	for _, arg := range args {
+		value, err := c.generateMinimalValue(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		vars[arg.Name] = value
+	}
 
-	return vars
+	return vars, nil
 }
 
-func (c *Crawler) GenerateMinimalTestDataForType(t *schema.Type) map[string]any {
+// GenerateMinimalTestDataForType generates a value for every required input field of the type
+func (c *Crawler) GenerateMinimalTestDataForType(t *schema.Type) (map[string]any, error) {
 	vars := make(map[string]any)
 
 	for _, f := range t.InputFields {
@@ -241,37 +609,43 @@ func (c *Crawler) GenerateMinimalTestDataForType(t *schema.Type) map[string]any
 			continue
 		}
 
-		baseType := f.Type.GetBaseType()
-
-		var value any
-		switch baseType.Kind {
-		case schema.EnumTypeKind:
-			value = c.schemaManager.Types[baseType.Name].EnumValues[0].Name
-		case schema.ScalarTypeKind:
-			if baseType.Name == "Boolean" {
-				value = true
-			} else if baseType.Name == "String" {
-				value = "0"
-			} else if baseType.Name == "Int" {
-				value = 0
-			} else if baseType.Name == "Float" {
-				value = 0.0
-			} else if baseType.Name == "ID" {
-				value = "0"
-			} else if baseType.Name == "Time" {
-				value = time.Now()
-			} else {
-				panic(fmt.Sprintf("Unhandled scalar type %s", baseType.Name))
-			}
-		case schema.InputObjectTypeKind:
-			completeBaseType := c.schemaManager.Types[baseType.Name]
-			value = c.GenerateMinimalTestDataForType(&completeBaseType)
-		default:
-			panic(fmt.Sprintf("Unimplemented variable kind %s", f.Type.Kind))
+		value, err := c.generateMinimalValue(f.Type)
+		if err != nil {
+			return nil, err
 		}
 
 		vars[f.Name] = value
 	}
 
-	return vars
+	return vars, nil
+}
+
+// generateMinimalValue generates the simplest value that is valid for the type
+func (c *Crawler) generateMinimalValue(t *schema.Type) (any, error) {
+	switch t.Kind {
+	case schema.NonNullTypeKind:
+		return c.generateMinimalValue(t.OfType)
+	case schema.ListTypeKind:
+		value, err := c.generateMinimalValue(t.OfType)
+		if err != nil {
+			return nil, err
+		}
+
+		return []any{value}, nil
+	case schema.EnumTypeKind:
+		values := c.schemaManager.Types[t.Name].EnumValues
+		if len(values) == 0 {
+			return nil, fmt.Errorf("the enum %s has no values", t.Name)
+		}
+
+		return values[0].Name, nil
+	case schema.ScalarTypeKind:
+		completeType := c.schemaManager.Types[t.Name]
+		return c.scalars.Generate(t.Name, completeType.SpecifiedByURL)
+	case schema.InputObjectTypeKind:
+		completeType := c.schemaManager.Types[t.Name]
+		return c.GenerateMinimalTestDataForType(&completeType)
+	default:
+		panic(fmt.Sprintf("Unimplemented variable kind %s", t.Kind))
+	}
 }
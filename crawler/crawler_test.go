@@ -0,0 +1,318 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/scalars"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"golang.org/x/exp/slices"
+)
+
+func Test_Batches(t *testing.T) {
+	op := func(identity string, t client.RequestType, skipped bool) CrawlOperation {
+		return CrawlOperation{Identity: identity, Type: t, Skipped: skipped}
+	}
+
+	ops := []CrawlOperation{
+		op("admin", client.QueryRequest, false),
+		op("user", client.QueryRequest, false),
+		op("admin", client.MutationRequest, false),
+		op("admin", client.QueryRequest, true),
+		op("admin", client.QueryRequest, false),
+		op("user", client.QueryRequest, false),
+		op("admin", client.QueryRequest, false),
+	}
+
+	tests := []struct {
+		name      string
+		batchSize int
+		want      [][]int
+	}{
+		{
+			name:      "NoBatching",
+			batchSize: 1,
+			want:      [][]int{{0}, {1}, {2}, {4}, {5}, {6}},
+		},
+		{
+			name:      "PerIdentity",
+			batchSize: 2,
+			want:      [][]int{{2}, {0, 4}, {1, 5}, {6}},
+		},
+		{
+			name:      "Large",
+			batchSize: 10,
+			want:      [][]int{{2}, {0, 4, 6}, {1, 5}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Crawler{cfg: Config{BatchSize: tt.batchSize}}
+			if got := c.batches(ops); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batches(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DoAll(t *testing.T) {
+	var mu sync.Mutex
+	var received []time.Time
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload client.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		received = append(received, time.Now())
+		mu.Unlock()
+
+		// Answers with the query, so that every operation can be matched to its response
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"query": payload.Query}})
+	}))
+	defer srv.Close()
+
+	const n = 10
+	const rate = 50.0
+
+	ops := make([]CrawlOperation, n)
+	for i := range ops {
+		ops[i] = NewOperation(fmt.Sprintf("op%d", i), *client.NewRequest(fmt.Sprintf("query { op%d }", i), nil))
+		ops[i].Type = client.QueryRequest
+	}
+
+	c := New(Config{
+		ClientConfig: introspection.Config{TargetUrl: srv.URL},
+		Concurrency:  4,
+		RateLimit:    rate,
+	})
+
+	start := time.Now()
+	c.doAll(ops)
+	elapsed := time.Since(start)
+
+	for i, op := range ops {
+		want := fmt.Sprintf(`"data":{"query":"query { op%d }"}`, i)
+		if !strings.Contains(op.Response, want) {
+			t.Errorf("doAll(), operation %d got response %s, want one with %s", i, op.Response, want)
+		}
+
+		if op.Outcome() != OutcomeAllowed {
+			t.Errorf("doAll(), operation %d got %v, want %v", i, op.Outcome(), OutcomeAllowed)
+		}
+	}
+
+	if len(received) != n {
+		t.Fatalf("doAll(), got %d requests, want %d", len(received), n)
+	}
+
+	// The limiter lets one request through per interval, the first one after a full interval
+	if min := time.Duration(float64(n-1) / rate * float64(time.Second)); elapsed < min {
+		t.Errorf("doAll(), took %v, want at least %v", elapsed, min)
+	}
+}
+
+func Test_NewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		wantNil bool
+	}{
+		{
+			name:    "no limit",
+			rate:    0,
+			wantNil: true,
+		},
+		{
+			name:    "negative",
+			rate:    -1,
+			wantNil: true,
+		},
+		{
+			name: "limited",
+			rate: 10,
+		},
+		{
+			name: "above a billion per second",
+			rate: 1e12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(tt.rate)
+			defer l.Stop()
+
+			if got := l == nil; got != tt.wantNil {
+				t.Fatalf("newRateLimiter(), got nil %v, want nil %v", got, tt.wantNil)
+			}
+
+			l.Wait()
+		})
+	}
+}
+
+func Test_GenerateMinimalValue(t *testing.T) {
+	s, err := sdl.Parse(`
+type Query { search(role: Role!, roles: [Role!], empty: Empty, file: Upload): [String] }
+
+enum Role { ADMIN USER }
+
+enum Empty
+
+scalar Upload
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Crawler{scalars: scalars.New()}
+	c.setSchema(s)
+
+	tests := []struct {
+		name    string
+		arg     string
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "enum",
+			arg:  "role",
+			want: "ADMIN",
+		},
+		{
+			name: "list of enums",
+			arg:  "roles",
+			want: []any{"ADMIN"},
+		},
+		{
+			name:    "enum without values",
+			arg:     "empty",
+			wantErr: true,
+		},
+		{
+			name:    "upload",
+			arg:     "file",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var arg schema.InputValue
+			for _, a := range c.schemaManager.Queries["search"].Args {
+				if a.Name == tt.arg {
+					arg = a
+				}
+			}
+
+			got, err := c.generateMinimalValue(arg.Type)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generateMinimalValue(), got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("generateMinimalValue(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CrawlUnreachable(t *testing.T) {
+	// The port is closed again, so that nothing is listening on it
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	c := New(Config{
+		ClientConfig: introspection.Config{TargetUrl: fmt.Sprintf("http://%s/graphql", l.Addr())},
+		SchemaFile:   "testdata/schema.graphql",
+	})
+
+	ops, err := c.Crawl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range ops {
+		if op.Skipped {
+			continue
+		}
+
+		if op.Error == nil {
+			t.Errorf("Crawl(), %s got no error, want one", op.Name)
+		}
+
+		if got := op.Outcome(); got != OutcomeFailed {
+			t.Errorf("Crawl(), %s got %v, want %v", op.Name, got, OutcomeFailed)
+		}
+	}
+}
+
+func Test_TestQuery(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"health":true}}`))
+	}))
+	defer srv.Close()
+
+	c := New(Config{
+		ClientConfig: introspection.Config{TargetUrl: srv.URL},
+		SchemaFile:   "testdata/schema.graphql",
+		Identities: []Identity{
+			{Name: "admin", Headers: map[string]string{"Authorization": "admin"}},
+			{Name: "user", Headers: map[string]string{"Authorization": "user"}},
+		},
+	})
+
+	ops, err := c.TestQuery("health")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var identities []string
+	for _, op := range ops {
+		identities = append(identities, op.Identity)
+		if op.Outcome() != OutcomeAllowed {
+			t.Errorf("TestQuery(), %s got %v, want %v", op.Identity, op.Outcome(), OutcomeAllowed)
+		}
+	}
+
+	if want := []string{"admin", "user"}; !reflect.DeepEqual(identities, want) {
+		t.Errorf("TestQuery(), got identities %v, want %v", identities, want)
+	}
+
+	slices.Sort(received)
+	if want := []string{"admin", "user"}; !reflect.DeepEqual(received, want) {
+		t.Errorf("TestQuery(), got requests as %v, want %v", received, want)
+	}
+
+	if _, err := c.TestQuery("unknown"); err == nil {
+		t.Errorf("TestQuery(), got no error for an unknown query")
+	}
+}
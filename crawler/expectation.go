@@ -0,0 +1,84 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+// Expectation is who an operation is supposed to be allowed for
+type Expectation string
+
+const (
+	// Allowed for every identity, including anonymous ones
+	ExpectPublic Expectation = "public"
+	// Allowed for identities with credentials and denied for anonymous ones
+	ExpectAuthenticated Expectation = "authenticated"
+	// Denied for every identity
+	ExpectDenied Expectation = "denied"
+)
+
+var Expectations = []Expectation{ExpectPublic, ExpectAuthenticated, ExpectDenied}
+
+func ParseExpectation(s string) (Expectation, error) {
+	for _, e := range Expectations {
+		if string(e) == s {
+			return e, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown expectation %s, must be one of %v", s, Expectations)
+}
+
+// expectationFor returns the expectation of the operation, keyed by its name or by its type and name like "mutation.user".
+// The type is needed when a query and a mutation have the same name, and takes precedence
+func expectationFor(expectations map[string]Expectation, t client.RequestType, name string) Expectation {
+	if e, ok := expectations[string(t)+"."+name]; ok {
+		return e
+	}
+
+	return expectations[name]
+}
+
+// ExpectationResult is whether the outcome of an operation met its expectation
+type ExpectationResult string
+
+const (
+	ExpectationPass ExpectationResult = "PASS"
+	ExpectationFail ExpectationResult = "FAIL"
+	// The operation has no expectation, or was not allowed nor denied
+	ExpectationNone ExpectationResult = ""
+)
+
+// ExpectationResult compares the outcome of the operation to its expectation
+func (o *CrawlOperation) ExpectationResult() ExpectationResult {
+	if o.Expectation == "" {
+		return ExpectationNone
+	}
+
+	var allowed bool
+	switch o.Outcome() {
+	case OutcomeAllowed:
+		allowed = true
+	case OutcomeDenied:
+		allowed = false
+	default:
+		return ExpectationNone
+	}
+
+	var wantAllowed bool
+	switch o.Expectation {
+	case ExpectPublic:
+		wantAllowed = true
+	case ExpectAuthenticated:
+		wantAllowed = o.Authenticated
+	case ExpectDenied:
+		wantAllowed = false
+	}
+
+	if allowed == wantAllowed {
+		return ExpectationPass
+	}
+
+	return ExpectationFail
+}
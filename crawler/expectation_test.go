@@ -0,0 +1,112 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+func Test_ExpectationResult(t *testing.T) {
+	tests := []struct {
+		name string
+		op   CrawlOperation
+		want ExpectationResult
+	}{
+		{
+			name: "no expectation",
+			op:   CrawlOperation{},
+			want: ExpectationNone,
+		},
+		{
+			name: "public allowed",
+			op:   CrawlOperation{Expectation: ExpectPublic},
+			want: ExpectationPass,
+		},
+		{
+			name: "public denied",
+			op:   CrawlOperation{Expectation: ExpectPublic, Denied: true},
+			want: ExpectationFail,
+		},
+		{
+			name: "authenticated allowed as anonymous",
+			op:   CrawlOperation{Expectation: ExpectAuthenticated},
+			want: ExpectationFail,
+		},
+		{
+			name: "authenticated denied as anonymous",
+			op:   CrawlOperation{Expectation: ExpectAuthenticated, Denied: true},
+			want: ExpectationPass,
+		},
+		{
+			name: "authenticated allowed with credentials",
+			op:   CrawlOperation{Expectation: ExpectAuthenticated, Authenticated: true},
+			want: ExpectationPass,
+		},
+		{
+			name: "denied allowed",
+			op:   CrawlOperation{Expectation: ExpectDenied, Authenticated: true},
+			want: ExpectationFail,
+		},
+		{
+			name: "skipped",
+			op:   CrawlOperation{Expectation: ExpectDenied, Skipped: true},
+			want: ExpectationNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op.ExpectationResult(); got != tt.want {
+				t.Errorf("ExpectationResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ExpectationFor(t *testing.T) {
+	expectations := map[string]Expectation{
+		"user":          ExpectPublic,
+		"mutation.user": ExpectDenied,
+		"me":            ExpectAuthenticated,
+	}
+
+	tests := []struct {
+		name   string
+		t      client.RequestType
+		opName string
+		want   Expectation
+	}{
+		{
+			name:   "by name",
+			t:      client.QueryRequest,
+			opName: "me",
+			want:   ExpectAuthenticated,
+		},
+		{
+			name:   "by type and name",
+			t:      client.MutationRequest,
+			opName: "user",
+			want:   ExpectDenied,
+		},
+		{
+			name:   "name of another type",
+			t:      client.QueryRequest,
+			opName: "user",
+			want:   ExpectPublic,
+		},
+		{
+			name:   "no expectation",
+			t:      client.QueryRequest,
+			opName: "users",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expectationFor(expectations, tt.t, tt.opName); got != tt.want {
+				t.Errorf("expectationFor(), got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
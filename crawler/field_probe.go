@@ -0,0 +1,197 @@
+package crawler
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
+	"github.com/fatih/color"
+	"golang.org/x/exp/slices"
+)
+
+// The outcome of a field probe where no data and no authorization error was returned for the field
+const OutcomeNoData Outcome = "NO DATA"
+
+// FieldProbe is the result of requesting a single leaf field of an operation
+type FieldProbe struct {
+	// The name of the root operation
+	Operation string `json:"operation"`
+	// The name of the identity the probe was performed as
+	Identity string `json:"identity"`
+	// The path to the field, starting at the root operation
+	Path string `json:"path"`
+
+	Outcome Outcome `json:"outcome"`
+
+	// The path of the authorization error, as reported by the server
+	ErrorPath string `json:"errorPath,omitempty"`
+}
+
+// ProbeFields requests every reachable leaf field of the allowed queries one at a time,
+// as the identity the query was allowed for, to find fields with their own authorization
+func (c *Crawler) ProbeFields(ops []CrawlOperation) []FieldProbe {
+	var probes []FieldProbe
+	var probeOps []CrawlOperation
+	var paths [][]string
+
+	for _, op := range ops {
+		// Mutations and subscriptions are not probed to avoid repeating their side effects
+		if op.Type != client.QueryRequest || op.Outcome() != OutcomeAllowed {
+			continue
+		}
+
+		f := c.schemaManager.Queries[op.Name]
+
+		for _, path := range c.schemaManager.LeafPaths(f) {
+			req := op.Request
+			req.Body = c.schemaManager.BuildPath(f, path, client.QueryRequest)
+
+			probeOp := NewOperation(op.Name, req)
+			probeOp.Identity = op.Identity
+			probeOp.Type = op.Type
+
+			probes = append(probes, FieldProbe{
+				Operation: op.Name,
+				Identity:  op.Identity,
+				Path:      formatPath(op.Name, path),
+			})
+			probeOps = append(probeOps, probeOp)
+			paths = append(paths, append([]string{op.Name}, path...))
+		}
+	}
+
+	c.doAll(probeOps)
+
+	for i := range probes {
+		probes[i].evaluate(probeOps[i], responsePath(paths[i]), c.classifier)
+	}
+
+	return probes
+}
+
+// evaluate sets the outcome of the probe from the result of its operation.
+// Authorization errors only count if they are reported at the field or one of its parents
+func (p *FieldProbe) evaluate(op CrawlOperation, path []string, cl *classifier.Classifier) {
+	if op.Failed {
+		p.Outcome = OutcomeFailed
+		return
+	}
+
+	resp, err := client.Parse([]byte(op.Response))
+	if err != nil || resp == nil {
+		p.Outcome = OutcomeFailed
+		return
+	}
+
+	for _, e := range resp.Errors {
+		if cl.ClassifyError(e).Verdict != classifier.VerdictDenied {
+			continue
+		}
+
+		errorPath := errorFieldPath(e)
+		if len(errorPath) > len(path) || !slices.Equal(errorPath, path[:len(errorPath)]) {
+			continue
+		}
+
+		p.Outcome = OutcomeDenied
+		p.ErrorPath = strings.Join(errorPath, ".")
+		return
+	}
+
+	if hasDataAt(resp.Data, path) {
+		p.Outcome = OutcomeAllowed
+	} else {
+		p.Outcome = OutcomeNoData
+	}
+}
+
+func (p *FieldProbe) PrintResult() {
+	p.WriteResult(os.Stdout)
+}
+
+// WriteResult writes the colored, human readable result of the probe
+func (p *FieldProbe) WriteResult(w io.Writer) {
+	var resultString string
+
+	switch p.Outcome {
+	case OutcomeDenied:
+		resultString = color.GreenString("DENIED")
+	case OutcomeAllowed:
+		resultString = color.RedString("ALLOWED")
+	default:
+		resultString = color.YellowString(string(p.Outcome))
+	}
+
+	if p.Identity != "" && p.Identity != DefaultIdentityName {
+		fmt.Fprintf(w, "\"%s\" as %s: %s\n", p.Path, p.Identity, resultString)
+	} else {
+		fmt.Fprintf(w, "\"%s\": %s\n", p.Path, resultString)
+	}
+}
+
+// formatPath joins the path with dots, writing inline fragments as <Type>
+func formatPath(operation string, path []string) string {
+	segments := []string{operation}
+	for _, segment := range path {
+		if typeName, ok := strings.CutPrefix(segment, "... on "); ok {
+			segment = fmt.Sprintf("<%s>", typeName)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// responsePath removes the inline fragments from the path, leaving the keys used in the response
+func responsePath(path []string) []string {
+	var keys []string
+	for _, segment := range path {
+		if strings.HasPrefix(segment, "... on ") {
+			continue
+		}
+
+		keys = append(keys, segment)
+	}
+
+	return keys
+}
+
+// errorFieldPath returns the field names of the path of the error, leaving out list indices
+func errorFieldPath(e client.Error) []string {
+	var path []string
+	for _, segment := range e.Path {
+		if s, ok := segment.(string); ok {
+			path = append(path, s)
+		}
+	}
+
+	return path
+}
+
+// hasDataAt checks if there is a non-null value at the path, looking through every item of lists
+func hasDataAt(data any, path []string) bool {
+	if data == nil {
+		return false
+	}
+
+	if len(path) == 0 {
+		return true
+	}
+
+	switch d := data.(type) {
+	case map[string]any:
+		return hasDataAt(d[path[0]], path[1:])
+	case []any:
+		for _, item := range d {
+			if hasDataAt(item, path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
+)
+
+func Test_FieldProbeEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		path     []string
+		want     Outcome
+	}{
+		{
+			name:     "Data",
+			response: `{"data":{"me":{"friends":[{"email":null},{"email":"a@b.c"}]}}}`,
+			path:     []string{"me", "friends", "email"},
+			want:     OutcomeAllowed,
+		},
+		{
+			name:     "FieldError",
+			response: `{"errors":[{"message":"PermissionDenied","path":["me","friends",1,"email"]}],"data":{"me":{"friends":[{"email":null}]}}}`,
+			path:     []string{"me", "friends", "email"},
+			want:     OutcomeDenied,
+		},
+		{
+			name:     "ParentError",
+			response: `{"errors":[{"message":"Unauthenticated","path":["me"]}],"data":null}`,
+			path:     []string{"me", "email"},
+			want:     OutcomeDenied,
+		},
+		{
+			name:     "OtherFieldError",
+			response: `{"errors":[{"message":"PermissionDenied","path":["me","phone"]}],"data":{"me":{"email":null}}}`,
+			path:     []string{"me", "email"},
+			want:     OutcomeNoData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FieldProbe{}
+			p.evaluate(CrawlOperation{Response: tt.response}, tt.path, classifier.Default())
+
+			if p.Outcome != tt.want {
+				t.Errorf("evaluate(), got %v, want %v", p.Outcome, tt.want)
+			}
+		})
+	}
+}
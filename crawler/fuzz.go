@@ -0,0 +1,404 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/fatih/color"
+	"golang.org/x/exp/slices"
+)
+
+type FuzzConfig struct {
+	// Seeds the generated values, the same seed generates the same requests for the same schema
+	Seed int64
+
+	// The number of requests to send per operation
+	Iterations int
+
+	// Fuzz the allowed mutations as well, repeating them with unusual input
+	Mutations bool
+}
+
+// FuzzIssue is a kind of response that indicates that the server did not handle its input
+type FuzzIssue string
+
+const (
+	// The server failed with an internal error instead of a validation error
+	FuzzInternalError FuzzIssue = "INTERNAL ERROR"
+	// The response contains traces of a panic or crash
+	FuzzPanic FuzzIssue = "PANIC"
+	// No response was received before the request timed out
+	FuzzTimeout FuzzIssue = "TIMEOUT"
+)
+
+// FuzzFinding is a request with fuzzed arguments that the server did not handle cleanly
+type FuzzFinding struct {
+	// The name of the root operation
+	Operation string             `json:"operation"`
+	Type      client.RequestType `json:"type"`
+	// The name of the identity the request was sent as
+	Identity string `json:"identity"`
+	// The argument that was fuzzed
+	Argument string `json:"argument"`
+
+	Issue FuzzIssue `json:"issue"`
+	// The error message or status that the issue was found from
+	Detail     string `json:"detail"`
+	StatusCode int    `json:"statusCode,omitempty"`
+
+	// The variables that were sent, to reproduce the finding
+	Variables map[string]any `json:"variables"`
+}
+
+// Limits of the fuzzed values, keeping the size of requests reasonable
+const (
+	maxFuzzDepth = 10
+	maxFuzzNodes = 2000
+	hugeListSize = 100
+)
+
+var fuzzInts = []int64{0, 1, -1, math.MaxInt32, math.MinInt32, math.MaxInt32 + 1, math.MinInt32 - 1, math.MaxInt64, math.MinInt64}
+
+var fuzzFloats = []float64{0, -1.5, 1e-7, math.SmallestNonzeroFloat64, math.MaxFloat64, -math.MaxFloat64}
+
+var fuzzStrings = []string{
+	"",
+	" ",
+	strings.Repeat("A", 100000),
+	"üñîçødé ✓ 中文 😀",
+	"\u202e\u0000\ufeff\u200b",
+	"\n\r\t",
+	"null",
+	"-1",
+	"1e309",
+	"NaN",
+	"' OR '1'='1",
+	"\"; DROP TABLE users; --",
+	"<script>alert(1)</script>",
+	"%s%s%n%x",
+	"../../../../etc/passwd",
+	"{{7*7}}",
+}
+
+var fuzzEnums = []string{"INVALID_ENUM_VALUE", "", "1", "null"}
+
+// Codes of errors that reject the input cleanly, some servers include stack traces with these as well
+var validationErrorCodes = []string{"BAD_USER_INPUT", "GRAPHQL_VALIDATION_FAILED", "GRAPHQL_PARSE_FAILED", "BAD_REQUEST", "VALIDATION_ERROR", "UNAUTHENTICATED", "FORBIDDEN"}
+
+// Messages of errors that leak a crash of the server
+var panicPatterns = []string{"panic", "runtime error", "nil pointer", "null pointer", "segmentation", "index out of range", "stack overflow", "goroutine "}
+
+// Messages and codes of errors that leak an unhandled failure of the server
+var internalErrorPatterns = []string{"internal server error", "internal error", "internal_server_error", "unexpected error", "exception", "traceback", "stacktrace", "sql syntax", "syntax error at or near"}
+
+// Fuzz sends requests with fuzzed arguments for every allowed query, and mutation if enabled, as the first identity it was allowed for.
+// Each request fuzzes one argument, taking turns, while the others keep the values of the crawl.
+// The requests that were not handled cleanly are returned, once per operation, identity, argument and issue
+func (c *Crawler) Fuzz(ops []CrawlOperation, cfg FuzzConfig) []FuzzFinding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := &fuzzer{
+		c:    c,
+		rand: rand.New(rand.NewSource(cfg.Seed)),
+	}
+
+	var fuzzOps []CrawlOperation
+	var arguments []string
+	fuzzed := make(map[string]bool)
+
+	for _, op := range ops {
+		// Subscriptions are not fuzzed as they are not answered with errors as reliably
+		if op.Type == client.SubscriptionRequest || op.Outcome() != OutcomeAllowed {
+			continue
+		}
+
+		if op.Type == client.MutationRequest && !cfg.Mutations {
+			continue
+		}
+
+		key := fmt.Sprintf("%s.%s", op.Type, op.Name)
+		if fuzzed[key] {
+			continue
+		}
+		fuzzed[key] = true
+
+		field, ok := c.schemaManager.Queries[op.Name]
+		if op.Type == client.MutationRequest {
+			field, ok = c.schemaManager.Mutations[op.Name]
+		}
+		if !ok {
+			continue
+		}
+
+		args := c.schemaManager.RequestArgs(field)
+		if len(args) == 0 {
+			continue
+		}
+
+		for i := 0; i < cfg.Iterations; i++ {
+			arg := args[i%len(args)]
+
+			vars := make(map[string]any, len(op.Request.Variables))
+			for k, v := range op.Request.Variables {
+				vars[k] = v
+			}
+			vars[arg.Name] = f.generate(arg.Type)
+
+			req := op.Request
+			req.Variables = vars
+
+			fuzzOp := NewOperation(op.Name, req)
+			fuzzOp.Identity = op.Identity
+			fuzzOp.Type = op.Type
+
+			fuzzOps = append(fuzzOps, fuzzOp)
+			arguments = append(arguments, arg.Name)
+		}
+	}
+
+	c.doAll(fuzzOps)
+
+	var findings []FuzzFinding
+	found := make(map[string]bool)
+	failed := 0
+
+	for i, op := range fuzzOps {
+		issue, detail := classifyFuzzResponse(op)
+		if issue == "" {
+			if op.Error != nil && op.StatusCode == 0 {
+				failed++
+			}
+			continue
+		}
+
+		key := fmt.Sprintf("%s.%s.%s.%s.%s", op.Type, op.Name, op.Identity, arguments[i], issue)
+		if found[key] {
+			continue
+		}
+		found[key] = true
+
+		findings = append(findings, FuzzFinding{
+			Operation:  op.Name,
+			Type:       op.Type,
+			Identity:   op.Identity,
+			Argument:   arguments[i],
+			Issue:      issue,
+			Detail:     detail,
+			StatusCode: op.StatusCode,
+			Variables:  op.Request.Variables,
+		})
+	}
+
+	if failed > 0 {
+		log.Printf("%d fuzzed requests failed without a response", failed)
+	}
+
+	return findings
+}
+
+// classifyFuzzResponse returns the issue indicated by the result of the operation, or an empty issue if the input was handled cleanly
+func classifyFuzzResponse(op CrawlOperation) (FuzzIssue, string) {
+	var netErr net.Error
+	if op.Error != nil && errors.As(op.Error, &netErr) && netErr.Timeout() {
+		return FuzzTimeout, op.Error.Error()
+	}
+
+	resp, err := client.Parse([]byte(op.Response))
+	if err == nil && resp != nil {
+		for _, e := range resp.Errors {
+			code := strings.ToUpper(fmt.Sprint(e.Extensions["code"]))
+			if slices.Contains(validationErrorCodes, code) {
+				continue
+			}
+
+			text := strings.ToLower(fmt.Sprintf("%s %s", e.Message, code))
+
+			if containsAny(text, panicPatterns) {
+				return FuzzPanic, e.Message
+			}
+
+			if containsAny(text, internalErrorPatterns) || e.Extensions["exception"] != nil || e.Extensions["stacktrace"] != nil {
+				return FuzzInternalError, e.Message
+			}
+		}
+	}
+
+	if op.StatusCode >= 500 {
+		return FuzzInternalError, fmt.Sprintf("status code %d", op.StatusCode)
+	}
+
+	// The connection being dropped without a response is likely a crash of the server,
+	// other errors such as a refused connection are failed requests rather than findings
+	if op.Error != nil && op.StatusCode == 0 && isDroppedConnection(op.Error) {
+		return FuzzPanic, op.Error.Error()
+	}
+
+	return "", ""
+}
+
+// isDroppedConnection reports whether the error is the server closing the connection before responding.
+// Replayed errors only keep their message, so it is matched for them instead
+func isDroppedConnection(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var replayed *client.ReplayedError
+	if !errors.As(err, &replayed) {
+		return false
+	}
+
+	return strings.HasSuffix(replayed.Message, "EOF") || strings.Contains(replayed.Message, "connection reset by peer")
+}
+
+func containsAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fuzzer generates unusual and invalid values for argument types
+type fuzzer struct {
+	c    *Crawler
+	rand *rand.Rand
+
+	// The number of values generated for the current argument, limiting the size of nested values
+	nodes int
+}
+
+// generate creates a fuzzed value for an argument of the type
+func (f *fuzzer) generate(t *schema.Type) any {
+	f.nodes = 0
+	return f.value(t, 0)
+}
+
+func (f *fuzzer) value(t *schema.Type, depth int) any {
+	f.nodes++
+
+	if t.Kind == schema.NonNullTypeKind {
+		return f.nonNullValue(t.OfType, depth)
+	}
+
+	if f.rand.Intn(4) == 0 {
+		return nil
+	}
+
+	return f.nonNullValue(t, depth)
+}
+
+func (f *fuzzer) nonNullValue(t *schema.Type, depth int) any {
+	// Past the limits the minimal value is used, keeping the value valid where it is nested
+	if depth > maxFuzzDepth || f.nodes > maxFuzzNodes {
+		v, _ := f.c.generateMinimalValue(t)
+		return v
+	}
+
+	switch t.Kind {
+	case schema.ListTypeKind:
+		var size int
+		switch f.rand.Intn(3) {
+		case 1:
+			size = 1
+		case 2:
+			size = hugeListSize
+		}
+
+		list := make([]any, size)
+		for i := range list {
+			list[i] = f.value(t.OfType, depth+1)
+		}
+
+		return list
+	case schema.EnumTypeKind:
+		values := f.c.schemaManager.Types[t.Name].EnumValues
+		if len(values) > 0 && f.rand.Intn(2) == 0 {
+			return values[f.rand.Intn(len(values))].Name
+		}
+
+		return fuzzEnums[f.rand.Intn(len(fuzzEnums))]
+	case schema.ScalarTypeKind:
+		return f.scalar(t.Name)
+	case schema.InputObjectTypeKind:
+		completeType := f.c.schemaManager.Types[t.Name]
+
+		obj := make(map[string]any)
+		for _, field := range completeType.InputFields {
+			// Nested input objects are included more often than other optional fields to reach deep values
+			required := field.Type.Kind == schema.NonNullTypeKind
+			nested := field.Type.GetBaseType().Kind == schema.InputObjectTypeKind
+			if !required && !(nested && f.rand.Intn(4) != 0) && f.rand.Intn(2) == 0 {
+				continue
+			}
+
+			obj[field.Name] = f.value(field.Type, depth+1)
+		}
+
+		return obj
+	default:
+		v, _ := f.c.generateMinimalValue(t)
+		return v
+	}
+}
+
+func (f *fuzzer) scalar(name string) any {
+	switch name {
+	case "Int":
+		return fuzzInts[f.rand.Intn(len(fuzzInts))]
+	case "Float":
+		return fuzzFloats[f.rand.Intn(len(fuzzFloats))]
+	case "Boolean":
+		return f.rand.Intn(2) == 0
+	case "String":
+		return fuzzStrings[f.rand.Intn(len(fuzzStrings))]
+	}
+
+	// IDs and custom scalars get either a valid value or any string or number
+	if f.rand.Intn(3) == 0 {
+		completeType := f.c.schemaManager.Types[name]
+		if v, err := f.c.scalars.Generate(name, completeType.SpecifiedByURL); err == nil {
+			return v
+		}
+	}
+
+	if f.rand.Intn(4) == 0 {
+		return fuzzInts[f.rand.Intn(len(fuzzInts))]
+	}
+
+	return fuzzStrings[f.rand.Intn(len(fuzzStrings))]
+}
+
+func (p *FuzzFinding) PrintResult() {
+	p.WriteResult(os.Stdout)
+}
+
+// WriteResult writes the colored, human readable finding
+func (p *FuzzFinding) WriteResult(w io.Writer) {
+	detail := p.Detail
+	if utf8.RuneCountInString(detail) > 200 {
+		detail = string([]rune(detail)[:200]) + "..."
+	}
+
+	resultString := fmt.Sprintf("%s fuzzing %s: %s", color.RedString(string(p.Issue)), p.Argument, detail)
+
+	if p.Identity != "" && p.Identity != DefaultIdentityName {
+		fmt.Fprintf(w, "\"%s\" as %s: %s\n", p.Operation, p.Identity, resultString)
+	} else {
+		fmt.Fprintf(w, "\"%s\": %s\n", p.Operation, resultString)
+	}
+}
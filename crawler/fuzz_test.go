@@ -0,0 +1,217 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema/scalars"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func Test_ClassifyFuzzResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		op   CrawlOperation
+		want FuzzIssue
+	}{
+		{
+			name: "ValidationError",
+			op:   CrawlOperation{StatusCode: 400, Response: `{"errors":[{"message":"Int cannot represent non 32-bit signed integer value"}]}`},
+			want: "",
+		},
+		{
+			name: "ValidationErrorWithStacktrace",
+			op:   CrawlOperation{StatusCode: 200, Response: `{"errors":[{"message":"Invalid input","extensions":{"code":"BAD_USER_INPUT","exception":{"stacktrace":[]}}}]}`},
+			want: "",
+		},
+		{
+			name: "InternalErrorCode",
+			op:   CrawlOperation{StatusCode: 200, Response: `{"errors":[{"message":"Something went wrong","extensions":{"code":"INTERNAL_SERVER_ERROR"}}]}`},
+			want: FuzzInternalError,
+		},
+		{
+			name: "StatusCode",
+			op:   CrawlOperation{StatusCode: 502, Response: `{}`, Error: errors.New("error parsing response")},
+			want: FuzzInternalError,
+		},
+		{
+			name: "Panic",
+			op:   CrawlOperation{StatusCode: 200, Response: `{"errors":[{"message":"runtime error: invalid memory address or nil pointer dereference"}]}`},
+			want: FuzzPanic,
+		},
+		{
+			name: "ConnectionLost",
+			op:   CrawlOperation{Response: `null`, Error: fmt.Errorf("error sending request: %w", &url.Error{Op: "Post", URL: "http://localhost/graphql", Err: io.EOF})},
+			want: FuzzPanic,
+		},
+		{
+			name: "ConnectionReset",
+			op:   CrawlOperation{Response: `null`, Error: fmt.Errorf("error sending request: %w", syscall.ECONNRESET)},
+			want: FuzzPanic,
+		},
+		{
+			name: "ReplayedConnectionLost",
+			op:   CrawlOperation{Response: `null`, Error: fmt.Errorf("error sending request: %w", &client.ReplayedError{Message: `Post "http://localhost/graphql": EOF`})},
+			want: FuzzPanic,
+		},
+		{
+			name: "ReplayedConnectionReset",
+			op:   CrawlOperation{Response: `null`, Error: fmt.Errorf("error sending request: %w", &client.ReplayedError{Message: `Post "http://localhost/graphql": read tcp: connection reset by peer`})},
+			want: FuzzPanic,
+		},
+		{
+			name: "EOFInMessage",
+			op:   CrawlOperation{Response: `null`, Error: errors.New("error parsing subscription message: unexpected EOF")},
+			want: "",
+		},
+		{
+			name: "ConnectionRefused",
+			op:   CrawlOperation{Response: `null`, Error: fmt.Errorf("error sending request: %w", syscall.ECONNREFUSED)},
+			want: "",
+		},
+		{
+			name: "NotRecorded",
+			op:   CrawlOperation{Response: `null`, Error: errors.New("no recorded response for the request")},
+			want: "",
+		},
+		{
+			name: "Timeout",
+			op:   CrawlOperation{Response: `null`, Error: timeoutError{}},
+			want: FuzzTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := classifyFuzzResponse(tt.op); got != tt.want {
+				t.Errorf("classifyFuzzResponse(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FuzzerSeed(t *testing.T) {
+	s, err := sdl.Parse(`
+type Query { search(filter: Filter!, limit: Int, role: Role!): [String] }
+
+input Filter { name: String, and: [Filter!], ids: [ID!]! }
+
+enum Role { ADMIN USER }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Crawler{scalars: scalars.New()}
+	c.setSchema(s)
+
+	generate := func(seed int64) string {
+		f := &fuzzer{c: c, rand: rand.New(rand.NewSource(seed))}
+
+		var values []any
+		for _, arg := range c.schemaManager.Queries["search"].Args {
+			for i := 0; i < 10; i++ {
+				values = append(values, f.generate(arg.Type))
+			}
+		}
+
+		b, err := json.Marshal(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return string(b)
+	}
+
+	if generate(1) != generate(1) {
+		t.Errorf("generate(), got different values for the same seed")
+	}
+
+	if generate(1) == generate(2) {
+		t.Errorf("generate(), got the same values for different seeds")
+	}
+}
+
+func Test_FuzzMutations(t *testing.T) {
+	var mu sync.Mutex
+	mutations := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload client.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if strings.HasPrefix(payload.Query, "mutation") {
+			mu.Lock()
+			mutations++
+			mu.Unlock()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		mutations bool
+		wantAny   bool
+	}{
+		{
+			name:      "queries only",
+			mutations: false,
+			wantAny:   false,
+		},
+		{
+			name:      "mutations enabled",
+			mutations: true,
+			wantAny:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{
+				ClientConfig: introspection.Config{TargetUrl: srv.URL},
+				SchemaFile:   "testdata/schema.graphql",
+			})
+
+			ops, err := c.Crawl()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			mutations = 0
+			mu.Unlock()
+
+			c.Fuzz(ops, FuzzConfig{Seed: 1, Iterations: 3, Mutations: tt.mutations})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got := mutations > 0; got != tt.wantAny {
+				t.Errorf("Fuzz(), got %d fuzzed mutations, want any: %v", mutations, tt.wantAny)
+			}
+		})
+	}
+}
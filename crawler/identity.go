@@ -0,0 +1,43 @@
+package crawler
+
+// The name of the identity used when no identities are configured
+const DefaultIdentityName = "default"
+
+// An Identity is a named set of headers that operations are performed as, eg. an anonymous user or an admin
+type Identity struct {
+	// The name used to refer to the identity in the results
+	Name string `json:"name" mapstructure:"name"`
+
+	// Headers to send with every request made as the identity
+	Headers map[string]string `json:"headers" mapstructure:"headers"`
+
+	// The payload to authenticate subscriptions with, the headers are used if not set
+	InitPayload map[string]any `json:"initPayload" mapstructure:"initPayload"`
+}
+
+// Anonymous reports whether the identity sends no credentials
+func (id Identity) Anonymous() bool {
+	return len(id.Headers) == 0 && len(id.InitPayload) == 0
+}
+
+// RequestHeaders returns the headers to send as the identity, the shared headers overridden by the ones of the identity.
+// Anonymous identities send no headers, as the shared headers may hold credentials
+func (id Identity) RequestHeaders(shared map[string]string) map[string]string {
+	if id.Anonymous() {
+		return nil
+	}
+
+	if len(shared) == 0 {
+		return id.Headers
+	}
+
+	headers := make(map[string]string, len(shared)+len(id.Headers))
+	for k, v := range shared {
+		headers[k] = v
+	}
+	for k, v := range id.Headers {
+		headers[k] = v
+	}
+
+	return headers
+}
@@ -0,0 +1,48 @@
+package crawler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_RequestHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     Identity
+		shared map[string]string
+		want   map[string]string
+	}{
+		{
+			name:   "no shared headers",
+			id:     Identity{Name: "admin", Headers: map[string]string{"Authorization": "admin"}},
+			shared: nil,
+			want:   map[string]string{"Authorization": "admin"},
+		},
+		{
+			name:   "merged",
+			id:     Identity{Name: "admin", Headers: map[string]string{"Authorization": "admin"}},
+			shared: map[string]string{"X-Tenant": "acme"},
+			want:   map[string]string{"Authorization": "admin", "X-Tenant": "acme"},
+		},
+		{
+			name:   "identity overrides",
+			id:     Identity{Name: "admin", Headers: map[string]string{"X-Tenant": "other"}},
+			shared: map[string]string{"X-Tenant": "acme"},
+			want:   map[string]string{"X-Tenant": "other"},
+		},
+		{
+			name:   "anonymous",
+			id:     Identity{Name: "anonymous"},
+			shared: map[string]string{"Authorization": "admin", "X-Tenant": "acme"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.RequestHeaders(tt.shared); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RequestHeaders(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package crawler
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/fatih/color"
+)
+
+// LimitDimension is a property of a request that servers limit to protect against expensive queries
+type LimitDimension string
+
+const (
+	// The number of nested selections
+	LimitDepth LimitDimension = "depth"
+	// The number of aliases of one field
+	LimitAliases LimitDimension = "aliases"
+	// The number of operations in an array batched request
+	LimitBatch LimitDimension = "batch"
+	// The number of times one field is repeated without aliases
+	LimitDuplicates LimitDimension = "duplicates"
+	// The number of directives in an operation
+	LimitDirectives LimitDimension = "directives"
+)
+
+var LimitDimensions = []LimitDimension{LimitDepth, LimitAliases, LimitBatch, LimitDuplicates, LimitDirectives}
+
+func ParseLimitDimension(s string) (LimitDimension, error) {
+	for _, d := range LimitDimensions {
+		if string(d) == s {
+			return d, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown limit %s, must be one of %v", s, LimitDimensions)
+}
+
+const DefaultLimitsCeiling = 128
+
+type LimitsConfig struct {
+	// The highest value to probe for every dimension, DefaultLimitsCeiling if 0. Must not be negative
+	Ceiling int
+
+	// The highest values the target is supposed to accept. Dimensions without one are only measured
+	Expected map[LimitDimension]int
+}
+
+// LimitResult is the highest value of a dimension accepted by the target
+type LimitResult struct {
+	Dimension LimitDimension `json:"dimension"`
+
+	// The query and identity the probes were sent as
+	Operation string `json:"operation,omitempty"`
+	Identity  string `json:"identity,omitempty"`
+
+	// The highest value accepted, 0 if even the smallest was rejected
+	MaxAccepted int `json:"maxAccepted"`
+	// The lowest value rejected, 0 if every value up to the ceiling was accepted
+	MinRejected int `json:"minRejected,omitempty"`
+	// The highest value that was probed
+	Ceiling int `json:"ceiling"`
+
+	// The highest value the target is supposed to accept, 0 if not configured
+	Expected int `json:"expected,omitempty"`
+
+	// The number of requests sent
+	Probes int `json:"probes"`
+
+	// Set if the dimension could not be probed
+	SkipReason string `json:"skipReason,omitempty"`
+}
+
+// Exceeded checks if the target accepted a value higher than it is supposed to
+func (r LimitResult) Exceeded() bool {
+	return r.SkipReason == "" && r.Expected > 0 && r.MaxAccepted > r.Expected
+}
+
+// limitProbe sends a request with the value of a dimension and checks if it was accepted
+type limitProbe func(value int) bool
+
+// Limits probes every dimension with increasing values up to the ceiling, using the first allowed query of the crawl.
+// Values are doubled until one is rejected, after which the highest accepted value is searched for between the two.
+// A value is accepted if the target returns data without errors that the query does not get on its own
+func (c *Crawler) Limits(ops []CrawlOperation, cfg LimitsConfig) ([]LimitResult, error) {
+	ceiling := cfg.Ceiling
+	if ceiling == 0 {
+		ceiling = DefaultLimitsCeiling
+	}
+	if ceiling < 1 {
+		return nil, fmt.Errorf("the limits ceiling must be at least 1, got %d", ceiling)
+	}
+
+	results := make([]LimitResult, len(LimitDimensions))
+	for i, d := range LimitDimensions {
+		results[i] = LimitResult{
+			Dimension: d,
+			Ceiling:   ceiling,
+			Expected:  cfg.Expected[d],
+		}
+	}
+
+	op, ok := limitsOperation(ops)
+	if !ok {
+		for i := range results {
+			results[i].SkipReason = "no query was allowed to probe with"
+		}
+		return results, nil
+	}
+
+	l := newRateLimiter(c.cfg.RateLimit)
+	defer l.Stop()
+
+	f := c.schemaManager.Queries[op.Name]
+	field := c.schemaManager.CompileFieldWithSelection(f, c.schemaManager.MinimalSelection(f))
+
+	// The errors of the query on its own are not caused by the probes
+	baseline := make(map[string]bool)
+	single := c.limitRequest(op, f, []string{field})
+	if resp, err := c.gqlClient.Execute(&single); err == nil && resp != nil {
+		for _, e := range resp.Errors {
+			baseline[e.Message] = true
+		}
+	}
+
+	execute := func(fields []string) bool {
+		l.Wait()
+		req := c.limitRequest(op, f, fields)
+		resp, err := c.gqlClient.Execute(&req)
+		return err == nil && limitAccepted(resp, baseline)
+	}
+
+	for i := range results {
+		r := &results[i]
+		r.Operation = op.Name
+		r.Identity = op.Identity
+
+		var probe limitProbe
+		switch r.Dimension {
+		case LimitDepth:
+			_, reached := c.schemaManager.NestedSelection(f, ceiling)
+			if reached < 2 {
+				r.SkipReason = fmt.Sprintf("the query %s does not select nested objects", op.Name)
+				continue
+			}
+
+			// The schema limits the depth of requests it can express
+			r.Ceiling = reached
+
+			probe = func(depth int) bool {
+				selection, _ := c.schemaManager.NestedSelection(f, depth)
+				return execute([]string{c.schemaManager.CompileFieldWithSelection(f, selection)})
+			}
+		case LimitAliases:
+			probe = func(n int) bool {
+				fields := make([]string, n)
+				for i := range fields {
+					fields[i] = fmt.Sprintf("alias%d: %s", i, field)
+				}
+				return execute(fields)
+			}
+		case LimitDuplicates:
+			probe = func(n int) bool {
+				return execute(repeat(field, n))
+			}
+		case LimitDirectives:
+			// Each directive is on its own fragment, as most directives may only be used once per location
+			probe = func(n int) bool {
+				return execute(append([]string{field}, repeat("... @include(if: true) {\n__typename\n}", n)...))
+			}
+		case LimitBatch:
+			probe = func(n int) bool {
+				l.Wait()
+				return c.batchAccepted(c.limitRequest(op, f, []string{field}), n, baseline)
+			}
+		}
+
+		r.MaxAccepted, r.MinRejected, r.Probes = searchLimit(probe, r.Ceiling)
+	}
+
+	return results, nil
+}
+
+// limitsOperation returns the first allowed query, which the probes are built from
+func limitsOperation(ops []CrawlOperation) (CrawlOperation, bool) {
+	for _, op := range ops {
+		if op.Type == client.QueryRequest && op.Outcome() == OutcomeAllowed {
+			return op, true
+		}
+	}
+
+	return CrawlOperation{}, false
+}
+
+// limitRequest builds a request of the compiled fields with the variables and headers of the operation
+func (c *Crawler) limitRequest(op CrawlOperation, f schema.Field, fields []string) client.Request {
+	req := op.Request
+	req.Body = c.schemaManager.BuildFields(f, fields, client.QueryRequest)
+
+	return req
+}
+
+// searchLimit doubles the value until it is rejected or reaches the ceiling, then finds the highest accepted value below the rejected one.
+// It returns the highest accepted value, the lowest rejected value and the number of probes
+func searchLimit(probe limitProbe, ceiling int) (int, int, int) {
+	accepted, rejected, probes := 0, 0, 0
+
+	for v := 1; rejected == 0; v *= 2 {
+		if v > ceiling {
+			v = ceiling
+		}
+
+		probes++
+		if probe(v) {
+			accepted = v
+		} else {
+			rejected = v
+		}
+
+		if v == ceiling {
+			break
+		}
+	}
+
+	for rejected > 0 && rejected-accepted > 1 {
+		v := (accepted + rejected) / 2
+
+		probes++
+		if probe(v) {
+			accepted = v
+		} else {
+			rejected = v
+		}
+	}
+
+	return accepted, rejected, probes
+}
+
+// limitAccepted checks if the response has data and no errors of the request as a whole that the baseline did not have.
+// Errors at a path are returned for single fields, meaning that the request was executed
+func limitAccepted(resp *client.Response, baseline map[string]bool) bool {
+	if resp == nil || resp.Data == nil {
+		return false
+	}
+
+	for _, e := range resp.Errors {
+		if len(e.Path) == 0 && !baseline[e.Message] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// batchAccepted sends the request n times as an array batch and checks if every request in it was accepted
+func (c *Crawler) batchAccepted(req client.Request, n int, baseline map[string]bool) bool {
+	reqs := make([]*client.Request, n)
+	for i := range reqs {
+		reqs[i] = &req
+	}
+
+	resps, err := c.gqlClient.ExecuteBatch(reqs)
+	if err != nil {
+		return false
+	}
+
+	for _, resp := range resps {
+		if !limitAccepted(resp, baseline) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func repeat(s string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = s
+	}
+
+	return lines
+}
+
+func (r *LimitResult) PrintResult() {
+	r.WriteResult(os.Stdout)
+}
+
+// WriteResult writes the colored, human readable result of the dimension
+func (r *LimitResult) WriteResult(w io.Writer) {
+	var result string
+
+	switch {
+	case r.SkipReason != "":
+		result = color.YellowString("SKIPPED: %s", r.SkipReason)
+	case r.MinRejected == 0:
+		result = fmt.Sprintf("no limit up to %d", r.MaxAccepted)
+	default:
+		result = fmt.Sprintf("%d accepted, %d rejected", r.MaxAccepted, r.MinRejected)
+	}
+
+	if r.Expected > 0 && r.SkipReason == "" {
+		verdict := color.GreenString("PASS")
+		if r.Exceeded() {
+			verdict = color.RedString("EXCEEDED")
+		}
+
+		result = fmt.Sprintf("%s, expected at most %d: %s", result, r.Expected, verdict)
+	}
+
+	fmt.Fprintf(w, "limit %s: %s\n", r.Dimension, result)
+}
@@ -0,0 +1,158 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+func Test_SearchLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		ceiling      int
+		wantAccepted int
+		wantRejected int
+	}{
+		{
+			name:         "PowerOfTwo",
+			limit:        16,
+			ceiling:      128,
+			wantAccepted: 16,
+			wantRejected: 17,
+		},
+		{
+			name:         "Between",
+			limit:        10,
+			ceiling:      128,
+			wantAccepted: 10,
+			wantRejected: 11,
+		},
+		{
+			name:         "NoneAccepted",
+			limit:        0,
+			ceiling:      128,
+			wantAccepted: 0,
+			wantRejected: 1,
+		},
+		{
+			name:         "Unlimited",
+			limit:        1000,
+			ceiling:      100,
+			wantAccepted: 100,
+			wantRejected: 0,
+		},
+		{
+			name:         "LimitAtCeiling",
+			limit:        99,
+			ceiling:      100,
+			wantAccepted: 99,
+			wantRejected: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var probed []int
+			probe := func(v int) bool {
+				if v > tt.ceiling {
+					t.Fatalf("probed %d above the ceiling %d", v, tt.ceiling)
+				}
+				probed = append(probed, v)
+				return v <= tt.limit
+			}
+
+			accepted, rejected, probes := searchLimit(probe, tt.ceiling)
+			if accepted != tt.wantAccepted || rejected != tt.wantRejected {
+				t.Errorf("searchLimit(), got %d accepted and %d rejected, want %d and %d", accepted, rejected, tt.wantAccepted, tt.wantRejected)
+			}
+			if probes != len(probed) {
+				t.Errorf("searchLimit(), reported %d probes, sent %d", probes, len(probed))
+			}
+		})
+	}
+}
+
+func Test_LimitAccepted(t *testing.T) {
+	baseline := map[string]bool{"field is deprecated": true}
+	data := map[string]any{"me": nil}
+
+	tests := []struct {
+		name string
+		resp *client.Response
+		want bool
+	}{
+		{
+			name: "NoResponse",
+			want: false,
+		},
+		{
+			name: "Data",
+			resp: &client.Response{Data: data},
+			want: true,
+		},
+		{
+			name: "FieldError",
+			resp: &client.Response{Data: data, Errors: []client.Error{{Message: "not found", Path: []any{"me"}}}},
+			want: true,
+		},
+		{
+			name: "BaselineError",
+			resp: &client.Response{Data: data, Errors: []client.Error{{Message: "field is deprecated"}}},
+			want: true,
+		},
+		{
+			name: "RequestError",
+			resp: &client.Response{Errors: []client.Error{{Message: "query is too deep"}}},
+			want: false,
+		},
+		{
+			name: "RequestErrorWithData",
+			resp: &client.Response{Data: data, Errors: []client.Error{{Message: "too many aliases"}}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := limitAccepted(tt.resp, baseline); got != tt.want {
+				t.Errorf("limitAccepted(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_LimitsCeiling(t *testing.T) {
+	tests := []struct {
+		name    string
+		ceiling int
+		wantErr bool
+	}{
+		{
+			name:    "Default",
+			ceiling: 0,
+			wantErr: false,
+		},
+		{
+			name:    "Positive",
+			ceiling: 1,
+			wantErr: false,
+		},
+		{
+			name:    "Negative",
+			ceiling: -4,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{})
+
+			_, err := c.Limits(nil, LimitsConfig{Ceiling: tt.ceiling})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Limits(), got error %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
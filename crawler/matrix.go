@@ -0,0 +1,86 @@
+package crawler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+// Matrix is the outcome of every operation as every identity
+type Matrix struct {
+	// The names of the identities, in the order they were configured
+	Identities []string `json:"identities"`
+
+	Operations []MatrixRow `json:"operations"`
+}
+
+type MatrixRow struct {
+	// The name of the operation
+	Name string `json:"name"`
+
+	// Whether the operation is a query, mutation or subscription
+	Type client.RequestType `json:"type"`
+
+	// The outcome of the operation keyed by identity name
+	Outcomes map[string]Outcome `json:"outcomes"`
+}
+
+// NewMatrix groups the operations by type and name, keeping the order in which they were performed
+func NewMatrix(identities []string, ops []CrawlOperation) Matrix {
+	m := Matrix{
+		Identities: identities,
+		Operations: make([]MatrixRow, 0),
+	}
+
+	// A query and a mutation may have the same name
+	type rowKey struct {
+		t    client.RequestType
+		name string
+	}
+
+	rowIndex := make(map[rowKey]int)
+	for _, op := range ops {
+		key := rowKey{op.Type, op.Name}
+		i, ok := rowIndex[key]
+		if !ok {
+			i = len(m.Operations)
+			rowIndex[key] = i
+			m.Operations = append(m.Operations, MatrixRow{
+				Name:     op.Name,
+				Type:     op.Type,
+				Outcomes: make(map[string]Outcome),
+			})
+		}
+
+		m.Operations[i].Outcomes[op.Identity] = op.Outcome()
+	}
+
+	return m
+}
+
+// Print writes the matrix as a table with one row per operation and one column per identity
+func (m Matrix) Print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "OPERATION\tTYPE\t%s\n", strings.Join(m.Identities, "\t"))
+
+	for _, row := range m.Operations {
+		cells := make([]string, len(m.Identities))
+		for i, id := range m.Identities {
+			outcome, ok := row.Outcomes[id]
+			if !ok {
+				cells[i] = "-"
+				continue
+			}
+
+			cells[i] = string(outcome)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", row.Name, row.Type, strings.Join(cells, "\t"))
+	}
+
+	tw.Flush()
+}
@@ -0,0 +1,54 @@
+package crawler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+)
+
+func Test_NewMatrix(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []CrawlOperation
+		want []MatrixRow
+	}{
+		{
+			name: "no operations",
+			ops:  nil,
+			want: []MatrixRow{},
+		},
+		{
+			name: "grouped by name",
+			ops: []CrawlOperation{
+				{Name: "users", Type: client.QueryRequest, Identity: "anonymous", Denied: true},
+				{Name: "me", Type: client.QueryRequest, Identity: "anonymous", Denied: true},
+				{Name: "users", Type: client.QueryRequest, Identity: "admin"},
+			},
+			want: []MatrixRow{
+				{Name: "users", Type: client.QueryRequest, Outcomes: map[string]Outcome{"anonymous": OutcomeDenied, "admin": OutcomeAllowed}},
+				{Name: "me", Type: client.QueryRequest, Outcomes: map[string]Outcome{"anonymous": OutcomeDenied}},
+			},
+		},
+		{
+			name: "query and mutation with the same name",
+			ops: []CrawlOperation{
+				{Name: "user", Type: client.QueryRequest, Identity: "admin"},
+				{Name: "user", Type: client.MutationRequest, Identity: "admin", Skipped: true},
+			},
+			want: []MatrixRow{
+				{Name: "user", Type: client.QueryRequest, Outcomes: map[string]Outcome{"admin": OutcomeAllowed}},
+				{Name: "user", Type: client.MutationRequest, Outcomes: map[string]Outcome{"admin": OutcomeSkipped}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMatrix([]string{"anonymous", "admin"}, tt.ops)
+			if !reflect.DeepEqual(got.Operations, tt.want) {
+				t.Errorf("NewMatrix(), got %v, want %v", got.Operations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// MutationMode is which mutations are performed when crawling
+type MutationMode string
+
+const (
+	// No mutations are performed
+	MutationsSkip MutationMode = "skip"
+	// Mutations are performed unless they are destructive
+	MutationsSafe MutationMode = "safe"
+	// Every mutation is performed, including destructive ones
+	MutationsAll MutationMode = "all"
+)
+
+var MutationModes = []MutationMode{MutationsSkip, MutationsSafe, MutationsAll}
+
+func ParseMutationMode(s string) (MutationMode, error) {
+	for _, m := range MutationModes {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown mutation mode %s, must be one of %v", s, MutationModes)
+}
+
+// The name patterns of mutations that are destructive, such as deleting data or sending messages
+var DefaultDestructivePatterns = []string{"delete*", "remove*", "purge*", "send*"}
+
+// The host patterns of production environments, which mutations are never performed against
+var DefaultProductionHosts = []string{"prod.*", "*.prod.*", "*-prod.*", "production.*", "*.production.*"}
+
+// ValidatePatterns checks that every pattern is a valid glob pattern, as invalid ones would never match
+func ValidatePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %s: %v", p, err)
+		}
+	}
+
+	return nil
+}
+
+// matchPattern returns the first of the glob patterns matching the name, ignoring case
+func matchPattern(patterns []string, name string) (string, bool) {
+	name = strings.ToLower(name)
+
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), name); ok {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// productionHost returns the host that operations are sent to if it matches a production host pattern
+func (c *Crawler) productionHost() (string, bool) {
+	u, err := url.Parse(c.gqlClient.Endpoint)
+	if err != nil {
+		return "", false
+	}
+
+	host := u.Hostname()
+	if _, ok := matchPattern(c.cfg.ProductionHosts, host); !ok {
+		return "", false
+	}
+
+	return host, true
+}
+
+// mutationSkipReason returns why the mutation must not be performed, or an empty string if it can be
+func (c *Crawler) mutationSkipReason(name string) string {
+	if host, ok := c.productionHost(); ok {
+		return fmt.Sprintf("mutations are never performed against the production host %s", host)
+	}
+
+	switch c.cfg.Mutations {
+	case MutationsAll:
+		return ""
+	case MutationsSkip:
+		return "mutations are skipped"
+	}
+
+	patterns := c.cfg.DestructivePatterns
+	if len(patterns) == 0 {
+		patterns = DefaultDestructivePatterns
+	}
+
+	if p, ok := matchPattern(patterns, name); ok {
+		return fmt.Sprintf("the mutation is destructive as it matches %s, use the mutation mode %s to perform it", p, MutationsAll)
+	}
+
+	return ""
+}
@@ -0,0 +1,125 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+)
+
+func Test_MutationSkipReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		mode     MutationMode
+		mutation string
+		skipped  bool
+	}{
+		{
+			name:     "SafeDefault",
+			target:   "http://localhost:4000/graphql",
+			mutation: "updateUser",
+			skipped:  false,
+		},
+		{
+			name:     "SafeDestructive",
+			target:   "http://localhost:4000/graphql",
+			mode:     MutationsSafe,
+			mutation: "DeleteUser",
+			skipped:  true,
+		},
+		{
+			name:     "Skip",
+			target:   "http://localhost:4000/graphql",
+			mode:     MutationsSkip,
+			mutation: "updateUser",
+			skipped:  true,
+		},
+		{
+			name:     "AllDestructive",
+			target:   "http://staging.example.com/graphql",
+			mode:     MutationsAll,
+			mutation: "sendEmail",
+			skipped:  false,
+		},
+		{
+			name:     "Production",
+			target:   "https://api.prod.example.com/graphql",
+			mode:     MutationsAll,
+			mutation: "updateUser",
+			skipped:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Crawler{
+				cfg: Config{
+					Mutations:       tt.mode,
+					ProductionHosts: DefaultProductionHosts,
+				},
+				gqlClient: client.New(tt.target),
+			}
+
+			reason := c.mutationSkipReason(tt.mutation)
+			if (reason != "") != tt.skipped {
+				t.Errorf("mutationSkipReason(%s), got \"%s\", want skipped %v", tt.mutation, reason, tt.skipped)
+			}
+		})
+	}
+}
+
+func Test_ValidatePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  bool
+	}{
+		{
+			name:     "Defaults",
+			patterns: append(DefaultDestructivePatterns, DefaultProductionHosts...),
+		},
+		{
+			name:     "Classes",
+			patterns: []string{"[dr]elete*", "send?"},
+		},
+		{
+			name:     "UnclosedClass",
+			patterns: []string{"delete*", "[remove*"},
+			wantErr:  true,
+		},
+		{
+			name:     "TrailingEscape",
+			patterns: []string{"purge\\"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePatterns(tt.patterns); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePatterns(), got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_MutationSkipReasonChangedTarget(t *testing.T) {
+	c := New(Config{
+		ClientConfig:    introspection.Config{TargetUrl: "http://staging.example.com/graphql"},
+		Mutations:       MutationsAll,
+		ProductionHosts: DefaultProductionHosts,
+	})
+
+	if reason := c.mutationSkipReason("updateUser"); reason != "" {
+		t.Fatalf("mutationSkipReason(), got \"%s\" before changing the target, want none", reason)
+	}
+
+	if err := c.SetTargetURL("https://api.prod.example.com/graphql"); err != nil {
+		t.Fatal(err)
+	}
+
+	if reason := c.mutationSkipReason("updateUser"); reason == "" {
+		t.Errorf("mutationSkipReason(), got none after changing the target to production, want the mutation refused")
+	}
+}
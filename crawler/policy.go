@@ -0,0 +1,131 @@
+package crawler
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/exp/slices"
+)
+
+// FailCondition is a kind of operation outcome that fails a crawl
+type FailCondition string
+
+const (
+	// Any operation was allowed
+	FailOnAllowed FailCondition = "allowed"
+	// An operation was allowed without being expected to be
+	FailOnUnexpectedAllowed FailCondition = "unexpected-allowed"
+	// An operation did not meet its expectation
+	FailOnExpectation FailCondition = "expectation"
+	// Any operation failed to be performed
+	FailOnFetchFailure FailCondition = "fetch-failure"
+	// Any operation got a verdict other than allowed, denied, failed or skipped
+	FailOnUnknown FailCondition = "unknown"
+)
+
+var FailConditions = []FailCondition{FailOnAllowed, FailOnUnexpectedAllowed, FailOnExpectation, FailOnFetchFailure, FailOnUnknown}
+
+func ParseFailCondition(s string) (FailCondition, error) {
+	for _, c := range FailConditions {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown fail condition %s, must be one of %v", s, FailConditions)
+}
+
+// Policy decides which operation outcomes fail a crawl
+type Policy struct {
+	FailOn []FailCondition
+}
+
+// Violation is an operation that fails the crawl
+type Violation struct {
+	Condition FailCondition `json:"condition"`
+	Name      string        `json:"name"`
+	Identity  string        `json:"identity"`
+	Outcome   Outcome       `json:"outcome"`
+}
+
+// Violations returns the operations violating the policy, in the order they were performed
+func (p Policy) Violations(ops []CrawlOperation) []Violation {
+	var violations []Violation
+	for _, op := range ops {
+		if c, ok := p.violatedBy(op); ok {
+			violations = append(violations, Violation{
+				Condition: c,
+				Name:      op.Name,
+				Identity:  op.Identity,
+				Outcome:   op.Outcome(),
+			})
+		}
+	}
+
+	return violations
+}
+
+// violatedBy returns the first condition of the policy the operation violates
+func (p Policy) violatedBy(op CrawlOperation) (FailCondition, bool) {
+	outcome := op.Outcome()
+
+	for _, c := range p.FailOn {
+		switch c {
+		case FailOnAllowed:
+			if outcome == OutcomeAllowed {
+				return c, true
+			}
+		case FailOnUnexpectedAllowed:
+			if outcome == OutcomeAllowed && op.ExpectationResult() != ExpectationPass {
+				return c, true
+			}
+		case FailOnExpectation:
+			if op.ExpectationResult() == ExpectationFail {
+				return c, true
+			}
+		case FailOnFetchFailure:
+			if outcome == OutcomeFailed || op.Error != nil {
+				return c, true
+			}
+		case FailOnUnknown:
+			if !slices.Contains([]Outcome{OutcomeAllowed, OutcomeDenied, OutcomeFailed, OutcomeSkipped}, outcome) {
+				return c, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// AllFailed reports whether every performed operation failed with an error, eg. as the target could not be reached.
+// Returns false if no operation was performed
+func AllFailed(ops []CrawlOperation) bool {
+	performed := 0
+	for _, op := range ops {
+		if op.Skipped {
+			continue
+		}
+
+		if op.Error == nil {
+			return false
+		}
+		performed++
+	}
+
+	return performed > 0
+}
+
+func PrintViolations(w io.Writer, violations []Violation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "Policy violations (%d):\n", len(violations))
+	for _, v := range violations {
+		if v.Identity == "" || v.Identity == DefaultIdentityName {
+			fmt.Fprintf(w, "\t%s: %s (%s)\n", v.Name, v.Outcome, v.Condition)
+		} else {
+			fmt.Fprintf(w, "\t%s as %s: %s (%s)\n", v.Name, v.Identity, v.Outcome, v.Condition)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package crawler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler/classifier"
+)
+
+func Test_PolicyViolations(t *testing.T) {
+	ops := []CrawlOperation{
+		{Name: "health", Expectation: ExpectPublic},
+		{Name: "users"},
+		{Name: "me", Denied: true},
+		{Name: "admin", Expectation: ExpectDenied},
+		{Name: "broken", Failed: true},
+		{Name: "unreachable", Error: errors.New("connection refused")},
+		{Name: "limited", Verdict: classifier.Verdict("RATE_LIMITED")},
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []string
+	}{
+		{
+			name:   "no conditions",
+			policy: Policy{},
+			want:   nil,
+		},
+		{
+			name:   "allowed",
+			policy: Policy{FailOn: []FailCondition{FailOnAllowed}},
+			want:   []string{"health", "users", "admin"},
+		},
+		{
+			name:   "unexpected allowed",
+			policy: Policy{FailOn: []FailCondition{FailOnUnexpectedAllowed}},
+			want:   []string{"users", "admin"},
+		},
+		{
+			name:   "expectation",
+			policy: Policy{FailOn: []FailCondition{FailOnExpectation}},
+			want:   []string{"admin"},
+		},
+		{
+			name:   "fetch failure and unknown",
+			policy: Policy{FailOn: []FailCondition{FailOnFetchFailure, FailOnUnknown}},
+			want:   []string{"broken", "unreachable", "limited"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := tt.policy.Violations(ops)
+
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.Name)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Violations() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Violations() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_AllFailed(t *testing.T) {
+	refused := errors.New("connection refused")
+
+	tests := []struct {
+		name string
+		ops  []CrawlOperation
+		want bool
+	}{
+		{
+			name: "no operations",
+			ops:  nil,
+			want: false,
+		},
+		{
+			name: "every operation failed",
+			ops:  []CrawlOperation{{Name: "users", Error: refused}, {Name: "me", Error: refused}, {Name: "deleteUser", Skipped: true}},
+			want: true,
+		},
+		{
+			name: "some operations failed",
+			ops:  []CrawlOperation{{Name: "users", Error: refused}, {Name: "me", Denied: true}},
+			want: false,
+		},
+		{
+			name: "only skipped operations",
+			ops:  []CrawlOperation{{Name: "deleteUser", Skipped: true}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AllFailed(tt.ops); got != tt.want {
+				t.Errorf("AllFailed(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
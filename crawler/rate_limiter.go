@@ -0,0 +1,44 @@
+package crawler
+
+import "time"
+
+// rateLimiter spaces out requests so that no more than a set number are made per second.
+// A nil rateLimiter does not limit anything
+type rateLimiter struct {
+	ticker *time.Ticker
+}
+
+// newRateLimiter creates a limiter allowing requestsPerSecond requests per second.
+// Returns nil if requestsPerSecond is not positive
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(float64(time.Second) / requestsPerSecond)
+	// Rates above a billion per second round down to no interval, which the ticker does not allow
+	if interval < 1 {
+		interval = 1
+	}
+
+	return &rateLimiter{
+		ticker: time.NewTicker(interval),
+	}
+}
+
+// Wait blocks until the next request is allowed to be made
+func (l *rateLimiter) Wait() {
+	if l == nil {
+		return
+	}
+
+	<-l.ticker.C
+}
+
+func (l *rateLimiter) Stop() {
+	if l == nil {
+		return
+	}
+
+	l.ticker.Stop()
+}
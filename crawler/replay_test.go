@@ -0,0 +1,49 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+)
+
+func Test_CrawlReplay(t *testing.T) {
+	cassette, err := client.LoadCassette("testdata/crawl.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(Config{
+		ClientConfig: introspection.Config{TargetUrl: "http://localhost:4000/graphql"},
+		SchemaFile:   "testdata/schema.graphql",
+		Identities: []Identity{
+			{Name: "admin", Headers: map[string]string{"Authorization": "admin"}},
+			{Name: "user", Headers: map[string]string{"Authorization": "user"}},
+			{Name: "anonymous"},
+		},
+		Cassette: cassette,
+	})
+
+	ops, err := c.Crawl()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]Outcome{
+		"health":     {"admin": OutcomeAllowed, "user": OutcomeAllowed, "anonymous": OutcomeAllowed},
+		"me":         {"admin": OutcomeAllowed, "user": OutcomeAllowed, "anonymous": OutcomeDenied},
+		"users":      {"admin": OutcomeAllowed, "user": OutcomeDenied, "anonymous": OutcomeDenied},
+		"deleteUser": {"admin": OutcomeSkipped, "user": OutcomeSkipped, "anonymous": OutcomeSkipped},
+		"updateUser": {"admin": OutcomeAllowed, "user": OutcomeAllowed, "anonymous": OutcomeDenied},
+	}
+
+	if len(ops) != 15 {
+		t.Fatalf("Crawl(), got %d operations, want 15", len(ops))
+	}
+
+	for _, op := range ops {
+		if got := op.Outcome(); got != want[op.Name][op.Identity] {
+			t.Errorf("Crawl(), %s as %s got %v, want %v", op.Name, op.Identity, got, want[op.Name][op.Identity])
+		}
+	}
+}
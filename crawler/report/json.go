@@ -0,0 +1,99 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+)
+
+type jsonReport struct {
+	Target      string               `json:"target"`
+	Identities  []string             `json:"identities"`
+	Summary     Summary              `json:"summary"`
+	Operations  []jsonOperation      `json:"operations"`
+	FieldProbes []crawler.FieldProbe `json:"fieldProbes,omitempty"`
+
+	FuzzSeed     int64                 `json:"fuzzSeed,omitempty"`
+	FuzzFindings []crawler.FuzzFinding `json:"fuzzFindings,omitempty"`
+
+	Limits []crawler.LimitResult `json:"limits,omitempty"`
+
+	SuggestionLeak *introspection.SuggestionLeak `json:"suggestionLeak,omitempty"`
+}
+
+type jsonOperation struct {
+	Name     string             `json:"name"`
+	Type     client.RequestType `json:"type"`
+	Identity string             `json:"identity"`
+	Outcome  crawler.Outcome    `json:"outcome"`
+
+	Expectation       crawler.Expectation       `json:"expectation,omitempty"`
+	ExpectationResult crawler.ExpectationResult `json:"expectationResult,omitempty"`
+
+	Rule       string `json:"rule,omitempty"`
+	SkipReason string `json:"skipReason,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	Query      string          `json:"query"`
+	Variables  map[string]any  `json:"variables,omitempty"`
+	StatusCode int             `json:"statusCode,omitempty"`
+	Response   json.RawMessage `json:"response,omitempty"`
+}
+
+func newJSONOperation(op crawler.CrawlOperation) jsonOperation {
+	o := jsonOperation{
+		Name:     op.Name,
+		Type:     op.Type,
+		Identity: op.Identity,
+		Outcome:  op.Outcome(),
+
+		Expectation:       op.Expectation,
+		ExpectationResult: op.ExpectationResult(),
+
+		Rule:       op.Rule,
+		SkipReason: op.SkipReason,
+		Query:      op.Request.Body,
+		Variables:  op.Request.Variables,
+		StatusCode: op.StatusCode,
+	}
+
+	if op.Error != nil {
+		o.Error = op.Error.Error()
+	}
+
+	// The response is embedded as is when it is valid json, which it is unless the request failed
+	if json.Valid([]byte(op.Response)) {
+		o.Response = json.RawMessage(op.Response)
+	}
+
+	return o
+}
+
+func writeJSON(w io.Writer, r Report) error {
+	jr := jsonReport{
+		Target:      r.Target,
+		Identities:  r.Identities,
+		Summary:     Summarize(r.Operations),
+		Operations:  make([]jsonOperation, len(r.Operations)),
+		FieldProbes: r.FieldProbes,
+
+		FuzzSeed:     r.FuzzSeed,
+		FuzzFindings: r.FuzzFindings,
+
+		Limits: r.Limits,
+
+		SuggestionLeak: r.SuggestionLeak,
+	}
+
+	for i, op := range r.Operations {
+		jr.Operations[i] = newJSONOperation(op)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(jr)
+}
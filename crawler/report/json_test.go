@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+)
+
+func Test_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, testReport()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+
+	if got.Summary.Total != 5 || got.Summary.Counts[crawler.OutcomeAllowed] != 4 || got.Summary.Counts[crawler.OutcomeDenied] != 1 {
+		t.Errorf("summary = %+v, want 5 operations of which 4 allowed and 1 denied", got.Summary)
+	}
+
+	if got.Summary.ExpectationsPassed != 1 || got.Summary.ExpectationsFailed != 1 {
+		t.Errorf("expectations passed, failed = %d, %d, want 1, 1", got.Summary.ExpectationsPassed, got.Summary.ExpectationsFailed)
+	}
+
+	if len(got.Operations) != 5 || got.Operations[4].ExpectationResult != crawler.ExpectationFail {
+		t.Errorf("operations = %+v, want 5 with the last failing its expectation", got.Operations)
+	}
+
+	if got.FuzzSeed != 42 || len(got.FuzzFindings) != 1 || got.FuzzFindings[0].Issue != crawler.FuzzInternalError {
+		t.Errorf("fuzzing = %d, %+v, want seed 42 and an internal error", got.FuzzSeed, got.FuzzFindings)
+	}
+
+	if len(got.Limits) != 3 || !got.Limits[1].Exceeded() {
+		t.Errorf("limits = %+v, want 3 with the aliases exceeded", got.Limits)
+	}
+
+	if got.SuggestionLeak == nil || got.SuggestionLeak.Names != 12 {
+		t.Errorf("suggestion leak = %+v, want 12 names", got.SuggestionLeak)
+	}
+}
+
+func Test_WriteJSONWithoutFindings(t *testing.T) {
+	r := testReport()
+	r.FuzzFindings, r.FuzzSeed, r.Limits, r.SuggestionLeak = nil, 0, nil, nil
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, r); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+
+	for _, key := range []string{"fuzzSeed", "fuzzFindings", "limits", "suggestionLeak"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("got %s, want it left out", key)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// newJUnitTestCase creates a test case for an outcome.
+// Allowed is a failure, denied passes, a failed fetch or unknown verdict is an error and skipped is skipped
+func newJUnitTestCase(name string, className string, outcome crawler.Outcome, detail string) junitTestCase {
+	tc := junitTestCase{
+		Name:      name,
+		ClassName: className,
+	}
+
+	switch outcome {
+	case crawler.OutcomeDenied:
+	case crawler.OutcomeAllowed:
+		tc.Failure = &junitMessage{Message: "allowed", Type: string(outcome), Body: detail}
+	case crawler.OutcomeSkipped:
+		tc.Skipped = &junitMessage{Message: detail}
+	default:
+		tc.Error = &junitMessage{Message: fmt.Sprintf("outcome %s", outcome), Type: string(outcome), Body: detail}
+	}
+
+	return tc
+}
+
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.TestCases = append(s.TestCases, tc)
+	s.Tests++
+
+	if tc.Failure != nil {
+		s.Failures++
+	}
+	if tc.Error != nil {
+		s.Errors++
+	}
+	if tc.Skipped != nil {
+		s.Skipped++
+	}
+}
+
+func writeJUnit(w io.Writer, r Report) error {
+	operations := junitTestSuite{Name: "operations"}
+	for _, op := range r.Operations {
+		detail := op.Response
+		if op.Outcome() == crawler.OutcomeSkipped {
+			detail = op.SkipReason
+		}
+
+		tc := newJUnitTestCase(operationTitle(op.Name, op.Identity), string(op.Type), op.Outcome(), detail)
+
+		// Operations with an expectation pass or fail by it instead of by the outcome
+		switch op.ExpectationResult() {
+		case crawler.ExpectationPass:
+			tc.Failure = nil
+		case crawler.ExpectationFail:
+			tc.Failure = &junitMessage{Message: fmt.Sprintf("expected %s", op.Expectation), Type: string(op.Outcome()), Body: detail}
+		}
+
+		operations.add(tc)
+	}
+
+	suites := junitTestSuites{
+		Name:   fmt.Sprintf("gts crawl %s", r.Target),
+		Suites: []junitTestSuite{operations},
+	}
+
+	if len(r.FieldProbes) > 0 {
+		probes := junitTestSuite{Name: "field probes"}
+		for _, p := range r.FieldProbes {
+			probes.add(newJUnitTestCase(operationTitle(p.Path, p.Identity), p.Operation, p.Outcome, p.ErrorPath))
+		}
+
+		suites.Suites = append(suites.Suites, probes)
+	}
+
+	if len(r.FuzzFindings) > 0 {
+		fuzzing := junitTestSuite{Name: fmt.Sprintf("fuzzing with seed %d", r.FuzzSeed)}
+		for _, f := range r.FuzzFindings {
+			fuzzing.add(junitTestCase{
+				Name:      fmt.Sprintf("%s fuzzing %s", operationTitle(f.Operation, f.Identity), f.Argument),
+				ClassName: string(f.Type),
+				Failure:   &junitMessage{Message: string(f.Issue), Type: string(f.Issue), Body: f.Detail},
+			})
+		}
+
+		suites.Suites = append(suites.Suites, fuzzing)
+	}
+
+	if len(r.Limits) > 0 {
+		limits := junitTestSuite{Name: "limits"}
+		for _, l := range r.Limits {
+			tc := junitTestCase{
+				Name:      string(l.Dimension),
+				ClassName: "limits",
+			}
+
+			switch {
+			case l.SkipReason != "":
+				tc.Skipped = &junitMessage{Message: l.SkipReason}
+			case l.Exceeded():
+				tc.Failure = &junitMessage{
+					Message: fmt.Sprintf("%d accepted, expected at most %d", l.MaxAccepted, l.Expected),
+					Type:    ruleLimitExceeded,
+				}
+			}
+
+			limits.add(tc)
+		}
+
+		suites.Suites = append(suites.Suites, limits)
+	}
+
+	if r.SuggestionLeak != nil {
+		schemaSuite := junitTestSuite{Name: "schema"}
+		schemaSuite.add(junitTestCase{
+			Name:      "suggestions",
+			ClassName: "schema",
+			Failure: &junitMessage{
+				Message: fmt.Sprintf("%d names were leaked through suggestions", r.SuggestionLeak.Names),
+				Type:    "suggestion-leak",
+				Body:    strings.Join(r.SuggestionLeak.Examples, "\n"),
+			},
+		})
+
+		suites.Suites = append(suites.Suites, schemaSuite)
+	}
+
+	for _, s := range suites.Suites {
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Errors += s.Errors
+		suites.Skipped += s.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
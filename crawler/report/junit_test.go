@@ -0,0 +1,36 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+)
+
+func Test_WriteJUnit(t *testing.T) {
+	r := Report{
+		Target: "http://localhost/graphql",
+		Operations: []crawler.CrawlOperation{
+			{Name: "users"},
+			{Name: "me", Denied: true},
+			{Name: "upload", Skipped: true, SkipReason: "unknown scalar"},
+			{Name: "broken", Failed: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, r); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid junit xml: %v", err)
+	}
+
+	if suites.Tests != 4 || suites.Failures != 1 || suites.Errors != 1 || suites.Skipped != 1 {
+		t.Errorf("tests, failures, errors, skipped = %d, %d, %d, %d, want 4, 1, 1, 1",
+			suites.Tests, suites.Failures, suites.Errors, suites.Skipped)
+	}
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+)
+
+var summaryOutcomes = []crawler.Outcome{
+	crawler.OutcomeAllowed,
+	crawler.OutcomeDenied,
+	crawler.OutcomeFailed,
+	crawler.OutcomeSkipped,
+}
+
+// writeMarkdown writes a summary suited for pull request comments
+func writeMarkdown(w io.Writer, r Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Crawl of %s\n\n", r.Target)
+
+	summary := Summarize(r.Operations)
+	b.WriteString("| Outcome | Operations |\n|---|---|\n")
+	for _, outcome := range summaryOutcomes {
+		fmt.Fprintf(&b, "| %s | %d |\n", outcome, summary.Counts[outcome])
+	}
+	for _, outcome := range summary.customOutcomes() {
+		fmt.Fprintf(&b, "| %s | %d |\n", outcome, summary.Counts[outcome])
+	}
+	fmt.Fprintf(&b, "| **Total** | %d |\n", summary.Total)
+
+	var allowed, unmet []crawler.CrawlOperation
+	for _, op := range r.Operations {
+		if op.Outcome() == crawler.OutcomeAllowed && op.ExpectationResult() != crawler.ExpectationPass {
+			allowed = append(allowed, op)
+		}
+		if op.ExpectationResult() == crawler.ExpectationFail {
+			unmet = append(unmet, op)
+		}
+	}
+
+	if len(allowed) > 0 {
+		b.WriteString("\n### Allowed operations\n\n| Operation | Type | Identity |\n|---|---|---|\n")
+		for _, op := range allowed {
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", op.Name, op.Type, op.Identity)
+		}
+	}
+
+	if len(unmet) > 0 {
+		b.WriteString("\n### Unmet expectations\n\n| Operation | Identity | Expected | Outcome |\n|---|---|---|---|\n")
+		for _, op := range unmet {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", op.Name, op.Identity, op.Expectation, op.Outcome())
+		}
+	}
+
+	if len(r.Identities) > 1 {
+		matrix := crawler.NewMatrix(r.Identities, r.Operations)
+
+		fmt.Fprintf(&b, "\n### Outcome per identity\n\n| Operation | Type | %s |\n|---|---|%s\n",
+			strings.Join(r.Identities, " | "), strings.Repeat("---|", len(r.Identities)))
+		for _, row := range matrix.Operations {
+			cells := make([]string, len(r.Identities))
+			for i, id := range r.Identities {
+				cells[i] = string(row.Outcomes[id])
+			}
+
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", row.Name, row.Type, strings.Join(cells, " | "))
+		}
+	}
+
+	var allowedFields []crawler.FieldProbe
+	for _, p := range r.FieldProbes {
+		if p.Outcome == crawler.OutcomeAllowed {
+			allowedFields = append(allowedFields, p)
+		}
+	}
+
+	if len(allowedFields) > 0 {
+		b.WriteString("\n### Allowed fields\n\n| Field | Identity |\n|---|---|\n")
+		for _, p := range allowedFields {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", p.Path, p.Identity)
+		}
+	}
+
+	if len(r.FuzzFindings) > 0 {
+		fmt.Fprintf(&b, "\n### Fuzzing findings\n\nFuzzed with seed %d\n\n| Operation | Identity | Argument | Issue | Detail |\n|---|---|---|---|---|\n", r.FuzzSeed)
+		for _, f := range r.FuzzFindings {
+			fmt.Fprintf(&b, "| `%s` | %s | `%s` | %s | %s |\n", f.Operation, f.Identity, f.Argument, f.Issue, markdownCell(f.Detail))
+		}
+	}
+
+	if len(r.Limits) > 0 {
+		b.WriteString("\n### Limits\n\n| Dimension | Accepted | Rejected | Expected at most | Result |\n|---|---|---|---|---|\n")
+		for _, l := range r.Limits {
+			rejected, expected, result := "-", "-", "measured"
+			if l.MinRejected > 0 {
+				rejected = fmt.Sprint(l.MinRejected)
+			}
+			if l.Expected > 0 {
+				expected = fmt.Sprint(l.Expected)
+				result = "pass"
+			}
+			switch {
+			case l.SkipReason != "":
+				result = "skipped: " + markdownCell(l.SkipReason)
+			case l.Exceeded():
+				result = "**exceeded**"
+			}
+
+			fmt.Fprintf(&b, "| %s | %d | %s | %s | %s |\n", l.Dimension, l.MaxAccepted, rejected, expected, result)
+		}
+	}
+
+	if r.SuggestionLeak != nil {
+		fmt.Fprintf(&b, "\n### Suggestion leak\n\nThe schema was recovered from suggestions in error messages, %d names were leaked.\n\n", r.SuggestionLeak.Names)
+		for _, e := range r.SuggestionLeak.Examples {
+			fmt.Fprintf(&b, "- %s\n", markdownCell(e))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell shortens the text to a single line that does not break the table
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "|", "\\|")
+	if utf8.RuneCountInString(s) > 100 {
+		s = string([]rune(s)[:100]) + "..."
+	}
+
+	return s
+}
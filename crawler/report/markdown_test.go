@@ -0,0 +1,70 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_WriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMarkdown, testReport()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"## Crawl of http://localhost/graphql",
+		"| **Total** | 5 |",
+		"### Allowed operations",
+		"| `deleteUser` | admin | denied | ALLOWED |",
+		"| `users` | query | ALLOWED | ALLOWED |",
+		"| `users.email` | anonymous |",
+		"Fuzzed with seed 42",
+		"| `users` | anonymous | `first` | INTERNAL ERROR | Int cannot represent \\| value: 2147483648 |",
+		"| aliases | 64 | 65 | 10 | **exceeded** |",
+		"| depth | 4 | - | 10 | pass |",
+		"| batch | 0 | - | - | skipped: batching is not supported |",
+		"The schema was recovered from suggestions in error messages, 12 names were leaked.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeMarkdown(), got no %q in\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, "users.password") {
+		t.Errorf("writeMarkdown(), got the denied field users.password")
+	}
+}
+
+func Test_MarkdownCell(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "short",
+			s:    "a | b\nc",
+			want: "a \\| b c",
+		},
+		{
+			name: "long",
+			s:    strings.Repeat("a", 120),
+			want: strings.Repeat("a", 100) + "...",
+		},
+		{
+			name: "multi-byte",
+			s:    strings.Repeat("ü", 120),
+			want: strings.Repeat("ü", 100) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownCell(tt.s); got != tt.want {
+				t.Errorf("markdownCell(), got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Package report writes the results of a crawl in human and machine readable formats
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/fatih/color"
+	"golang.org/x/exp/slices"
+)
+
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatJUnit    Format = "junit"
+	FormatSARIF    Format = "sarif"
+	FormatMarkdown Format = "markdown"
+)
+
+var Formats = []Format{FormatText, FormatJSON, FormatJUnit, FormatSARIF, FormatMarkdown}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown report format %s, must be one of %v", s, Formats)
+}
+
+// Report holds the results of a crawl
+type Report struct {
+	// The graphql endpoint that was crawled
+	Target string
+
+	// The names of the identities the operations were performed as
+	Identities []string
+
+	Operations []crawler.CrawlOperation
+
+	FieldProbes []crawler.FieldProbe
+
+	// The seed the arguments were fuzzed with and the requests that were not handled cleanly, if fuzzed
+	FuzzSeed     int64
+	FuzzFindings []crawler.FuzzFinding
+
+	// The highest values accepted by the target per limit dimension, if probed
+	Limits []crawler.LimitResult
+
+	// Set if the schema was recovered and the target suggested names of its schema in errors
+	SuggestionLeak *introspection.SuggestionLeak
+}
+
+// Write writes the report in the format
+func Write(w io.Writer, f Format, r Report) error {
+	switch f {
+	case FormatText:
+		return writeText(w, r)
+	case FormatJSON:
+		return writeJSON(w, r)
+	case FormatJUnit:
+		return writeJUnit(w, r)
+	case FormatSARIF:
+		return writeSARIF(w, r)
+	case FormatMarkdown:
+		return writeMarkdown(w, r)
+	default:
+		return fmt.Errorf("unknown report format %s", f)
+	}
+}
+
+func writeText(w io.Writer, r Report) error {
+	if r.SuggestionLeak != nil {
+		fmt.Fprintf(w, "%s the target suggests names of its schema in errors, %d names were leaked\n\n", color.YellowString("WARNING"), r.SuggestionLeak.Names)
+	}
+
+	for _, op := range r.Operations {
+		op.WriteResult(w)
+	}
+
+	if len(r.FieldProbes) > 0 {
+		fmt.Fprintln(w)
+		for _, p := range r.FieldProbes {
+			p.WriteResult(w)
+		}
+	}
+
+	if len(r.FuzzFindings) > 0 {
+		fmt.Fprintln(w)
+		for _, f := range r.FuzzFindings {
+			f.WriteResult(w)
+		}
+	}
+
+	if len(r.Limits) > 0 {
+		fmt.Fprintln(w)
+		for _, l := range r.Limits {
+			l.WriteResult(w)
+		}
+	}
+
+	return nil
+}
+
+// Summary counts the operations per outcome
+type Summary struct {
+	Total  int                     `json:"total"`
+	Counts map[crawler.Outcome]int `json:"counts"`
+
+	// The number of operations that did and did not meet their expectation
+	ExpectationsPassed int `json:"expectationsPassed"`
+	ExpectationsFailed int `json:"expectationsFailed"`
+}
+
+func Summarize(ops []crawler.CrawlOperation) Summary {
+	s := Summary{
+		Total:  len(ops),
+		Counts: make(map[crawler.Outcome]int),
+	}
+
+	for _, op := range ops {
+		s.Counts[op.Outcome()]++
+
+		switch op.ExpectationResult() {
+		case crawler.ExpectationPass:
+			s.ExpectationsPassed++
+		case crawler.ExpectationFail:
+			s.ExpectationsFailed++
+		}
+	}
+
+	return s
+}
+
+// Print writes the counts of the known outcomes followed by any custom verdicts
+func (s Summary) Print(w io.Writer) {
+	fmt.Fprintf(w, "Summary: %d operations\n", s.Total)
+	for _, outcome := range summaryOutcomes {
+		fmt.Fprintf(w, "\t%s: %d\n", outcome, s.Counts[outcome])
+	}
+	for _, outcome := range s.customOutcomes() {
+		fmt.Fprintf(w, "\t%s: %d\n", outcome, s.Counts[outcome])
+	}
+
+	if s.ExpectationsPassed+s.ExpectationsFailed > 0 {
+		fmt.Fprintf(w, "Expectations: %d passed, %d failed\n", s.ExpectationsPassed, s.ExpectationsFailed)
+	}
+}
+
+// customOutcomes returns the sorted outcomes produced by custom classifier verdicts
+func (s Summary) customOutcomes() []crawler.Outcome {
+	var others []crawler.Outcome
+	for outcome := range s.Counts {
+		if !slices.Contains(summaryOutcomes, outcome) {
+			others = append(others, outcome)
+		}
+	}
+	slices.Sort(others)
+
+	return others
+}
+
+// operationTitle names the operation and the identity it was performed as
+func operationTitle(name string, identity string) string {
+	if identity == "" || identity == crawler.DefaultIdentityName {
+		return name
+	}
+
+	return fmt.Sprintf("%s as %s", name, identity)
+}
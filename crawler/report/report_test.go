@@ -0,0 +1,47 @@
+package report
+
+import (
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+)
+
+// testReport has a result in every section of a report
+func testReport() Report {
+	return Report{
+		Target:     "http://localhost/graphql",
+		Identities: []string{"anonymous", "admin"},
+		Operations: []crawler.CrawlOperation{
+			{Name: "users", Type: client.QueryRequest, Identity: "anonymous"},
+			{Name: "users", Type: client.QueryRequest, Identity: "admin"},
+			{Name: "me", Type: client.QueryRequest, Identity: "anonymous", Denied: true},
+			{Name: "health", Type: client.QueryRequest, Identity: "anonymous", Expectation: crawler.ExpectPublic},
+			{Name: "deleteUser", Type: client.MutationRequest, Identity: "admin", Expectation: crawler.ExpectDenied, Authenticated: true},
+		},
+		FieldProbes: []crawler.FieldProbe{
+			{Operation: "users", Identity: "anonymous", Path: "users.email", Outcome: crawler.OutcomeAllowed},
+			{Operation: "users", Identity: "anonymous", Path: "users.password", Outcome: crawler.OutcomeDenied},
+		},
+		FuzzSeed: 42,
+		FuzzFindings: []crawler.FuzzFinding{
+			{
+				Operation: "users",
+				Type:      client.QueryRequest,
+				Identity:  "anonymous",
+				Argument:  "first",
+				Issue:     crawler.FuzzInternalError,
+				Detail:    "Int cannot represent | value:\n2147483648",
+				Variables: map[string]any{"first": 2147483648},
+			},
+		},
+		Limits: []crawler.LimitResult{
+			{Dimension: crawler.LimitDepth, Operation: "users", MaxAccepted: 4, Ceiling: 4, Expected: 10},
+			{Dimension: crawler.LimitAliases, Operation: "users", MaxAccepted: 64, MinRejected: 65, Ceiling: 128, Expected: 10},
+			{Dimension: crawler.LimitBatch, SkipReason: "batching is not supported"},
+		},
+		SuggestionLeak: &introspection.SuggestionLeak{
+			Names:    12,
+			Examples: []string{`Cannot query field "user" on type "Query". Did you mean "users"?`},
+		},
+	}
+}
@@ -0,0 +1,209 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+)
+
+// Implemented as specified by https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+const (
+	ruleAllowedOperation = "allowed-operation"
+	ruleAllowedField     = "allowed-field"
+	ruleFuzzFinding      = "fuzz-finding"
+	ruleSuggestionLeak   = "suggestion-leak"
+	ruleLimitExceeded    = "limit-exceeded"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// writeSARIF writes every allowed operation and field, every fuzzing finding, every exceeded limit and a suggestion leak as results,
+// except operations expected to be allowed
+func writeSARIF(w io.Writer, r Report) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name: "gts",
+				Rules: []sarifRule{
+					{ID: ruleAllowedOperation, ShortDescription: sarifMessage{Text: "The operation was allowed"}},
+					{ID: ruleAllowedField, ShortDescription: sarifMessage{Text: "The field was allowed"}},
+					{ID: ruleFuzzFinding, ShortDescription: sarifMessage{Text: "Fuzzed arguments were not handled cleanly"}},
+					{ID: ruleSuggestionLeak, ShortDescription: sarifMessage{Text: "Names of the schema are suggested in error messages"}},
+					{ID: ruleLimitExceeded, ShortDescription: sarifMessage{Text: "A request exceeding the expected limit was accepted"}},
+				},
+			},
+		},
+		Results: make([]sarifResult, 0),
+	}
+
+	for _, op := range r.Operations {
+		if op.Outcome() != crawler.OutcomeAllowed || op.ExpectationResult() == crawler.ExpectationPass {
+			continue
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  ruleAllowedOperation,
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("The %s %s was allowed as %s", op.Type, op.Name, op.Identity)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               op.Name,
+					FullyQualifiedName: fmt.Sprintf("%s.%s", op.Type, op.Name),
+					Kind:               "function",
+				}},
+			}},
+			Properties: map[string]any{
+				"identity": op.Identity,
+				"target":   r.Target,
+			},
+		})
+	}
+
+	for _, p := range r.FieldProbes {
+		if p.Outcome != crawler.OutcomeAllowed {
+			continue
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  ruleAllowedField,
+			Level:   "warning",
+			Message: sarifMessage{Text: fmt.Sprintf("The field %s was allowed as %s", p.Path, p.Identity)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               p.Path,
+					FullyQualifiedName: p.Path,
+					Kind:               "member",
+				}},
+			}},
+			Properties: map[string]any{
+				"identity": p.Identity,
+				"target":   r.Target,
+			},
+		})
+	}
+
+	for _, f := range r.FuzzFindings {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  ruleFuzzFinding,
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("The %s %s failed with %s when fuzzing %s: %s", f.Type, f.Operation, f.Issue, f.Argument, f.Detail)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               f.Argument,
+					FullyQualifiedName: fmt.Sprintf("%s.%s(%s:)", f.Type, f.Operation, f.Argument),
+					Kind:               "parameter",
+				}},
+			}},
+			Properties: map[string]any{
+				"identity": f.Identity,
+				"target":   r.Target,
+				"seed":     r.FuzzSeed,
+			},
+		})
+	}
+
+	for _, l := range r.Limits {
+		if !l.Exceeded() {
+			continue
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  ruleLimitExceeded,
+			Level:   "error",
+			Message: sarifMessage{Text: fmt.Sprintf("A %s of %d was accepted, expected at most %d", l.Dimension, l.MaxAccepted, l.Expected)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               l.Operation,
+					FullyQualifiedName: fmt.Sprintf("query.%s", l.Operation),
+					Kind:               "function",
+				}},
+			}},
+			Properties: map[string]any{
+				"dimension": l.Dimension,
+				"identity":  l.Identity,
+				"target":    r.Target,
+			},
+		})
+	}
+
+	if r.SuggestionLeak != nil {
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  ruleSuggestionLeak,
+			Level:   "warning",
+			Message: sarifMessage{Text: fmt.Sprintf("The schema was recovered from suggestions in error messages, %d names were leaked", r.SuggestionLeak.Names)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               r.Target,
+					FullyQualifiedName: r.Target,
+					Kind:               "module",
+				}},
+			}},
+			Properties: map[string]any{
+				"target":   r.Target,
+				"examples": r.SuggestionLeak.Examples,
+			},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
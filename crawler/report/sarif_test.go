@@ -0,0 +1,51 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, testReport()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid sarif: %v", err)
+	}
+
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("version, runs = %s, %d, want %s, 1", log.Version, len(log.Runs), sarifVersion)
+	}
+
+	rules := make(map[string]bool)
+	for _, r := range log.Runs[0].Tool.Driver.Rules {
+		rules[r.ID] = true
+	}
+
+	got := make(map[string]int)
+	for _, r := range log.Runs[0].Results {
+		if !rules[r.RuleID] {
+			t.Errorf("result of undeclared rule %s", r.RuleID)
+		}
+
+		got[r.RuleID]++
+	}
+
+	// The health query is expected to be allowed and the denied operations, field and limits are not reported
+	want := map[string]int{
+		ruleAllowedOperation: 3,
+		ruleAllowedField:     1,
+		ruleFuzzFinding:      1,
+		ruleLimitExceeded:    1,
+		ruleSuggestionLeak:   1,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results per rule = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+// Package selector matches operations of a schema by name, kind, return type, deprecation and arguments
+package selector
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+)
+
+// A Selector matches operations. Selectors are written as one or more terms joined by "&", all of which must match.
+// A "&" that is part of a term, eg. of a regular expression, is escaped as "\&":
+//
+//	users, admin*       the name of the operation, with glob patterns
+//	/^internal[A-Z]/    a regular expression matching the name of the operation
+//	kind:mutation       the kind of operation, one of query, mutation or subscription
+//	returns:User*       the name of the type returned by the operation, with glob patterns
+//	arg:*Input          the name of the type of any argument of the operation, with glob patterns
+//	deprecated          operations that are deprecated
+type Selector struct {
+	source string
+	terms  []term
+}
+
+type term func(kind client.RequestType, f schema.Field) bool
+
+func Parse(s string) (Selector, error) {
+	sel := Selector{source: s}
+
+	for _, part := range splitTerms(s) {
+		t, err := parseTerm(strings.TrimSpace(part))
+		if err != nil {
+			return Selector{}, fmt.Errorf("invalid selector %s: %v", s, err)
+		}
+
+		sel.terms = append(sel.terms, t)
+	}
+
+	return sel, nil
+}
+
+// MustParse is like Parse but panics if the selector is invalid, for selectors that are known to be valid
+func MustParse(s string) Selector {
+	sel, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return sel
+}
+
+// splitTerms splits the selector on every "&" that is not escaped, unescaping the escaped ones.
+// Other backslashes are kept, as they are part of regular expressions
+func splitTerms(s string) []string {
+	var terms []string
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '&':
+			b.WriteByte('&')
+			i++
+		case s[i] == '&':
+			terms = append(terms, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return append(terms, b.String())
+}
+
+func parseTerm(s string) (term, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty term")
+	}
+
+	if s == "deprecated" {
+		return func(_ client.RequestType, f schema.Field) bool {
+			return f.IsDeprecated
+		}, nil
+	}
+
+	if len(s) > 1 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/") {
+		re, err := regexp.Compile(s[1 : len(s)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		return func(_ client.RequestType, f schema.Field) bool {
+			return re.MatchString(f.Name)
+		}, nil
+	}
+
+	if kind, ok := strings.CutPrefix(s, "kind:"); ok {
+		switch client.RequestType(kind) {
+		case client.QueryRequest, client.MutationRequest, client.SubscriptionRequest:
+		default:
+			return nil, fmt.Errorf("unknown kind %s", kind)
+		}
+
+		return func(k client.RequestType, _ schema.Field) bool {
+			return k == client.RequestType(kind)
+		}, nil
+	}
+
+	if pattern, ok := strings.CutPrefix(s, "returns:"); ok {
+		match, err := glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(_ client.RequestType, f schema.Field) bool {
+			return f.Type != nil && match(f.Type.GetBaseType().Name)
+		}, nil
+	}
+
+	if pattern, ok := strings.CutPrefix(s, "arg:"); ok {
+		match, err := glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(_ client.RequestType, f schema.Field) bool {
+			for _, arg := range f.Args {
+				if arg.Type != nil && match(arg.Type.GetBaseType().Name) {
+					return true
+				}
+			}
+
+			return false
+		}, nil
+	}
+
+	match, err := glob(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(_ client.RequestType, f schema.Field) bool {
+		return match(f.Name)
+	}, nil
+}
+
+// glob validates the pattern and returns a function matching names to it
+func glob(pattern string) (func(string) bool, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+	}
+
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+// Matches reports whether the operation of the kind matches every term of the selector
+func (s Selector) Matches(kind client.RequestType, f schema.Field) bool {
+	for _, t := range s.terms {
+		if !t(kind, f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s Selector) String() string {
+	return s.source
+}
+
+// Set matches operations matching any of its selectors
+type Set []Selector
+
+// ParseSet parses every selector, failing on the first invalid one
+func ParseSet(selectors []string) (Set, error) {
+	set := make(Set, 0, len(selectors))
+	for _, s := range selectors {
+		sel, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+
+		set = append(set, sel)
+	}
+
+	return set, nil
+}
+
+// Strings returns the selectors as they were written
+func (s Set) Strings() []string {
+	strs := make([]string, len(s))
+	for i, sel := range s {
+		strs[i] = sel.String()
+	}
+
+	return strs
+}
+
+func (s Set) String() string {
+	return fmt.Sprint(s.Strings())
+}
+
+func (s Set) Matches(kind client.RequestType, f schema.Field) bool {
+	for _, sel := range s {
+		if sel.Matches(kind, f) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package selector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+)
+
+func Test_Matches(t *testing.T) {
+	userType := &schema.Type{Kind: schema.NonNullTypeKind, OfType: &schema.Type{Kind: schema.ObjectTypeKind, Name: "User"}}
+	inputType := &schema.Type{Kind: schema.InputObjectTypeKind, Name: "DeleteUserInput"}
+
+	deleteUser := schema.Field{
+		Name:         "adminDeleteUser",
+		Type:         userType,
+		Args:         []schema.InputValue{{Name: "input", Type: inputType}},
+		IsDeprecated: true,
+	}
+
+	tests := []struct {
+		selector string
+		kind     client.RequestType
+		want     bool
+	}{
+		{selector: "adminDeleteUser", kind: client.MutationRequest, want: true},
+		{selector: "admin*", kind: client.MutationRequest, want: true},
+		{selector: "internal*", kind: client.MutationRequest, want: false},
+		{selector: "/Delete/", kind: client.MutationRequest, want: true},
+		{selector: "/^Delete/", kind: client.MutationRequest, want: false},
+		{selector: "kind:mutation", kind: client.MutationRequest, want: true},
+		{selector: "kind:query", kind: client.MutationRequest, want: false},
+		{selector: "returns:User", kind: client.MutationRequest, want: true},
+		{selector: "returns:Post", kind: client.MutationRequest, want: false},
+		{selector: "arg:*Input", kind: client.MutationRequest, want: true},
+		{selector: "arg:String", kind: client.MutationRequest, want: false},
+		{selector: "deprecated", kind: client.MutationRequest, want: true},
+		{selector: "kind:mutation & admin*", kind: client.MutationRequest, want: true},
+		{selector: "kind:query&admin*", kind: client.MutationRequest, want: false},
+		{selector: `/^(admin\&|admin)Delete/`, kind: client.MutationRequest, want: true},
+		{selector: `/[\&]/ & kind:mutation`, kind: client.MutationRequest, want: false},
+		{selector: `/\w+User$/&kind:mutation`, kind: client.MutationRequest, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := s.Matches(tt.kind, deleteUser); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "kind:fragment", "/[/", "returns:[", "admin*&"} {
+		t.Run(s, func(t *testing.T) {
+			if _, err := Parse(s); err == nil {
+				t.Errorf("Parse(%q) error = nil, want error", s)
+			}
+		})
+	}
+}
+
+func Test_SplitTerms(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{
+			name: "single",
+			s:    "admin*",
+			want: []string{"admin*"},
+		},
+		{
+			name: "joined",
+			s:    "kind:mutation&admin*",
+			want: []string{"kind:mutation", "admin*"},
+		},
+		{
+			name: "escaped",
+			s:    `/a\&b/&kind:query`,
+			want: []string{"/a&b/", "kind:query"},
+		},
+		{
+			name: "other escapes kept",
+			s:    `/\d\&/`,
+			want: []string{`/\d&/`},
+		},
+		{
+			name: "trailing",
+			s:    "admin*&",
+			want: []string{"admin*", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitTerms(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitTerms(), got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_SetStrings(t *testing.T) {
+	set, err := ParseSet([]string{"admin*", `/a\&b/`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"admin*", `/a\&b/`}
+	if got := set.Strings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Strings(), got %q, want %q", got, want)
+	}
+}
@@ -7,4 +7,10 @@ type Config struct {
 	HttpPort string
 
 	CrawlerConfig crawler.Config
+
+	// Crawl the added and changed operations when polling finds changes to the schema
+	AutoCrawl bool
+
+	// An url to post a summary of every automatic crawl to
+	WebhookUrl string
 }
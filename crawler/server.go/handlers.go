@@ -7,12 +7,13 @@ import (
 	"net/http"
 
 	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/crawler/report"
 	"github.com/TheLeeeo/gql-test-suite/introspection"
 	"github.com/julienschmidt/httprouter"
 )
 
 func (s *Server) Crawl(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	ops, err := s.crawler.Crawl()
+	ops, _, err := s.crawl(s.crawler.Crawl)
 	if err != nil {
 		if err == introspection.ErrNoTargetAddr {
 			w.WriteHeader(http.StatusBadRequest)
@@ -27,9 +28,27 @@ func (s *Server) Crawl(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 		return
 	}
 
+	if r.URL.Query().Get("matrix") == "true" {
+		b, err := json.Marshal(crawler.NewMatrix(s.crawler.GetIdentities(), ops))
+		if err != nil {
+			log.Println("error marshalling matrix: ", err)
+
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, "error marshalling matrix")
+			return
+		}
+
+		w.Write(b)
+		return
+	}
+
 	failedOps := make([]crawler.CrawlOperation, 0)
 	for _, op := range ops {
-		if op.Error != nil || !op.Denied {
+		if op.ExpectationResult() == crawler.ExpectationPass {
+			continue
+		}
+
+		if op.Error != nil || !op.Denied || op.ExpectationResult() == crawler.ExpectationFail {
 			failedOps = append(failedOps, op)
 		}
 	}
@@ -45,6 +64,34 @@ func (s *Server) Crawl(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 	w.Write(b)
 }
 
+// GetReport returns the report of the latest crawl, in the format of the format query parameter, json by default
+func (s *Server) GetReport(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	format := report.FormatJSON
+	if f := r.URL.Query().Get("format"); f != "" {
+		var err error
+		format, err = report.ParseFormat(f)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, err)
+			return
+		}
+	}
+
+	s.latestMu.Lock()
+	latest := s.latest
+	s.latestMu.Unlock()
+
+	if latest == nil {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, "no crawl has been performed yet")
+		return
+	}
+
+	if err := report.Write(w, format, *latest); err != nil {
+		log.Println("error writing report: ", err)
+	}
+}
+
 func (s *Server) GetIgnore(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	b, err := json.Marshal(s.crawler.GetIgnore())
 	if err != nil {
@@ -65,12 +112,50 @@ func (s *Server) SetIgnore(w http.ResponseWriter, r *http.Request, _ httprouter.
 		return
 	}
 
-	s.crawler.SetIgnore(ignore)
+	if err := s.crawler.SetIgnore(ignore); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, "error setting ignore list: ", err)
+		return
+	}
+
 	log.Println("Updated ignore list to: ", ignore)
 
 	fmt.Fprint(w, ignore)
 }
 
+func (s *Server) GetExpectations(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	b, err := json.Marshal(s.crawler.GetExpectations())
+	if err != nil {
+		log.Println("error marshalling expectations: ", err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	w.Write(b)
+}
+
+func (s *Server) SetExpectations(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var expectations map[string]crawler.Expectation
+	if err := json.NewDecoder(r.Body).Decode(&expectations); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, "error decoding expectations, please format as an object ({\"operation\": \"public\"})")
+		return
+	}
+
+	for name, e := range expectations {
+		if _, err := crawler.ParseExpectation(string(e)); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "invalid expectation for operation %s: %v\n", name, err)
+			return
+		}
+	}
+
+	s.crawler.SetExpectations(expectations)
+	log.Println("Updated expectations to: ", expectations)
+
+	fmt.Fprint(w, expectations)
+}
+
 func (s *Server) SetTargetURL(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	var newUrl string
 	if err := json.NewDecoder(r.Body).Decode(&newUrl); err != nil {
@@ -3,8 +3,11 @@ package crawlserver
 import (
 	"log"
 	"net/http"
+	"sync"
 
 	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/crawler/report"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -12,6 +15,13 @@ type Server struct {
 	crawler *crawler.Crawler
 
 	cfg Config
+
+	// Held from the start of a crawl until its report is stored, so that the latest report is of the crawl that finished last
+	crawlMu sync.Mutex
+
+	// The report of the latest crawl, nil until a crawl has been performed
+	latest   *report.Report
+	latestMu sync.Mutex
 }
 
 func New(cfg Config) *Server {
@@ -26,7 +36,11 @@ func New(cfg Config) *Server {
 func (s *Server) Run() error {
 	router := s.SetupRouter()
 
-	s.crawler.StartPolling()
+	var onChange func([]diff.Change)
+	if s.cfg.AutoCrawl {
+		onChange = s.crawlChanges
+	}
+	s.crawler.StartPolling(onChange)
 
 	log.Println("Starting crawl server on ", s.cfg.HttpPort)
 	return http.ListenAndServe(s.cfg.HttpPort, router)
@@ -39,6 +53,11 @@ func (s *Server) SetupRouter() *httprouter.Router {
 	router.GET("/ignore", s.GetIgnore)
 	router.POST("/ignore", s.SetIgnore)
 
+	router.GET("/expectations", s.GetExpectations)
+	router.POST("/expectations", s.SetExpectations)
+
+	router.GET("/report", s.GetReport)
+
 	router.GET("/target", s.GetTargetURL)
 	router.POST("/target", s.SetTargetURL)
 
@@ -51,3 +70,54 @@ func (s *Server) PanicHandler(w http.ResponseWriter, r *http.Request, err interf
 	w.WriteHeader(http.StatusInternalServerError)
 	log.Println("Panic: ", err)
 }
+
+// crawlChanges crawls the operations affected by the schema changes, keeping the result as the latest report
+func (s *Server) crawlChanges(changes []diff.Change) {
+	ops, r, err := s.crawl(func() ([]crawler.CrawlOperation, error) {
+		return s.crawler.CrawlChanged(changes)
+	})
+	if err != nil {
+		log.Println("error crawling changed operations: ", err)
+		return
+	}
+
+	log.Printf("Crawled %d operations affected by the schema changes", len(ops))
+
+	if s.cfg.WebhookUrl == "" {
+		return
+	}
+
+	if err := postWebhook(s.cfg.WebhookUrl, newWebhookPayload(r, changes)); err != nil {
+		log.Println("error posting to webhook: ", err)
+	}
+}
+
+// crawl performs the crawl and stores its result as the latest report, one crawl at a time
+func (s *Server) crawl(crawl func() ([]crawler.CrawlOperation, error)) ([]crawler.CrawlOperation, report.Report, error) {
+	s.crawlMu.Lock()
+	defer s.crawlMu.Unlock()
+
+	ops, err := crawl()
+	if err != nil {
+		return nil, report.Report{}, err
+	}
+
+	return ops, s.setLatest(ops), nil
+}
+
+// setLatest stores the result of a crawl as the latest report
+func (s *Server) setLatest(ops []crawler.CrawlOperation) report.Report {
+	r := report.Report{
+		Target:     s.crawler.GetTargetURL(),
+		Identities: s.crawler.GetIdentities(),
+		Operations: ops,
+
+		SuggestionLeak: s.crawler.GetSuggestionLeak(),
+	}
+
+	s.latestMu.Lock()
+	defer s.latestMu.Unlock()
+	s.latest = &r
+
+	return r
+}
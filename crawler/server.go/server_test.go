@@ -0,0 +1,142 @@
+package crawlserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/introspection"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
+)
+
+// newTestServer creates a server crawling a target that allows every operation
+func newTestServer(t *testing.T, webhookUrl string) *Server {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(target.Close)
+
+	return New(Config{
+		CrawlerConfig: crawler.Config{
+			ClientConfig: introspection.Config{TargetUrl: target.URL},
+			SchemaFile:   "../testdata/schema.graphql",
+		},
+		AutoCrawl:  webhookUrl != "",
+		WebhookUrl: webhookUrl,
+	})
+}
+
+func serve(s *Server, method string, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.SetupRouter().ServeHTTP(w, httptest.NewRequest(method, url, nil))
+
+	return w
+}
+
+func Test_GetReport(t *testing.T) {
+	s := newTestServer(t, "")
+
+	if w := serve(s, http.MethodGet, "/report"); w.Code != http.StatusNotFound {
+		t.Fatalf("GET /report before crawling, got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	if w := serve(s, http.MethodPost, "/crawl"); w.Code != http.StatusOK {
+		t.Fatalf("POST /crawl, got status %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "json by default",
+			url:        "/report",
+			wantStatus: http.StatusOK,
+			want:       `"total": 5`,
+		},
+		{
+			name:       "markdown",
+			url:        "/report?format=markdown",
+			wantStatus: http.StatusOK,
+			want:       "## Crawl of http://",
+		},
+		{
+			name:       "unknown format",
+			url:        "/report?format=xml",
+			wantStatus: http.StatusBadRequest,
+			want:       "unknown report format xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(s, http.MethodGet, tt.url)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GET %s, got status %d, want %d", tt.url, w.Code, tt.wantStatus)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("GET %s, got %s, want it to contain %s", tt.url, w.Body, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CrawlChanges(t *testing.T) {
+	payloads := make(chan webhookPayload, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("error decoding payload: %v", err)
+		}
+		payloads <- p
+	}))
+	defer webhook.Close()
+
+	s := newTestServer(t, webhook.URL)
+
+	// Loads the schema, as polling would before finding changes
+	if w := serve(s, http.MethodPost, "/crawl"); w.Code != http.StatusOK {
+		t.Fatalf("POST /crawl, got status %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	s.crawlChanges([]diff.Change{{Kind: diff.FieldAdded, Severity: diff.Safe, Path: "Query.health"}})
+
+	p := <-payloads
+	if p.Summary.Total != 1 || len(p.Allowed) != 1 || p.Allowed[0] != "health" {
+		t.Errorf("crawlChanges(), got webhook payload %+v, want the health query allowed", p)
+	}
+
+	if w := serve(s, http.MethodGet, "/report"); !strings.Contains(w.Body.String(), `"total": 1`) {
+		t.Errorf("GET /report, got %s, want the report of the automatic crawl", w.Body)
+	}
+}
+
+func Test_ConcurrentCrawls(t *testing.T) {
+	s := newTestServer(t, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			serve(s, http.MethodPost, "/crawl")
+		}()
+		go func() {
+			defer wg.Done()
+			s.crawlChanges([]diff.Change{{Kind: diff.FieldAdded, Severity: diff.Safe, Path: "Query.health"}})
+		}()
+	}
+	wg.Wait()
+
+	if w := serve(s, http.MethodGet, "/report"); w.Code != http.StatusOK {
+		t.Errorf("GET /report, got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
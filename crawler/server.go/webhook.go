@@ -0,0 +1,77 @@
+package crawlserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/crawler/report"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// webhookPayload summarizes an automatic crawl.
+// The text field makes it readable by chat webhooks, eg. slack, as it is
+type webhookPayload struct {
+	Text          string         `json:"text"`
+	Target        string         `json:"target"`
+	SchemaChanges []diff.Change  `json:"schemaChanges"`
+	Summary       report.Summary `json:"summary"`
+	// The operations that were allowed, formatted like "name" or "name as identity"
+	Allowed []string `json:"allowed"`
+}
+
+func newWebhookPayload(r report.Report, changes []diff.Change) webhookPayload {
+	summary := report.Summarize(r.Operations)
+
+	allowed := make([]string, 0)
+	for _, op := range r.Operations {
+		if op.Outcome() != crawler.OutcomeAllowed || op.ExpectationResult() == crawler.ExpectationPass {
+			continue
+		}
+
+		if op.Identity == "" || op.Identity == crawler.DefaultIdentityName {
+			allowed = append(allowed, op.Name)
+		} else {
+			allowed = append(allowed, fmt.Sprintf("%s as %s", op.Name, op.Identity))
+		}
+	}
+
+	text := fmt.Sprintf("The schema of %s changed (%s). Crawled %d affected operations: %d allowed, %d denied, %d failed, %d skipped",
+		r.Target, diff.Summary(changes), summary.Total,
+		summary.Counts[crawler.OutcomeAllowed], summary.Counts[crawler.OutcomeDenied],
+		summary.Counts[crawler.OutcomeFailed], summary.Counts[crawler.OutcomeSkipped])
+
+	return webhookPayload{
+		Text:          text,
+		Target:        r.Target,
+		SchemaChanges: changes,
+		Summary:       summary,
+		Allowed:       allowed,
+	}
+}
+
+func postWebhook(url string, payload webhookPayload) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshalling payload: %v", err)
+	}
+
+	c := http.Client{Timeout: webhookTimeout}
+
+	resp, err := c.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package crawlserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/crawler"
+	"github.com/TheLeeeo/gql-test-suite/crawler/report"
+	"github.com/TheLeeeo/gql-test-suite/schema/diff"
+)
+
+func Test_NewWebhookPayload(t *testing.T) {
+	r := report.Report{
+		Target: "http://localhost/graphql",
+		Operations: []crawler.CrawlOperation{
+			{Name: "users", Identity: crawler.DefaultIdentityName},
+			{Name: "me", Identity: "admin"},
+			{Name: "admin", Identity: "anonymous", Denied: true},
+			{Name: "health", Identity: "anonymous", Expectation: crawler.ExpectPublic},
+			{Name: "deleteUser", Identity: "admin", Skipped: true},
+		},
+	}
+	changes := []diff.Change{
+		{Kind: diff.FieldAdded, Severity: diff.Safe, Path: "Query.me"},
+		{Kind: diff.FieldRemoved, Severity: diff.Breaking, Path: "Query.user"},
+	}
+
+	p := newWebhookPayload(r, changes)
+
+	// The health query is expected to be allowed
+	wantAllowed := []string{"users", "me as admin"}
+	if !reflect.DeepEqual(p.Allowed, wantAllowed) {
+		t.Errorf("newWebhookPayload(), got allowed %v, want %v", p.Allowed, wantAllowed)
+	}
+
+	wantText := "The schema of http://localhost/graphql changed (1 breaking, 0 dangerous, 1 safe). Crawled 5 affected operations: 3 allowed, 1 denied, 0 failed, 1 skipped"
+	if p.Text != wantText {
+		t.Errorf("newWebhookPayload(), got text %q, want %q", p.Text, wantText)
+	}
+
+	if len(p.SchemaChanges) != 2 || p.Summary.Total != 5 {
+		t.Errorf("newWebhookPayload(), got %d changes and %d operations, want 2 and 5", len(p.SchemaChanges), p.Summary.Total)
+	}
+}
+
+func Test_PostWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{
+			name:   "accepted",
+			status: http.StatusOK,
+		},
+		{
+			name:   "no content",
+			status: http.StatusNoContent,
+		},
+		{
+			name:    "rejected",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got webhookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("got content type %s, want application/json", ct)
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("error decoding payload: %v", err)
+				}
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			payload := webhookPayload{Text: "The schema changed", Target: "http://localhost/graphql", Allowed: []string{"users"}}
+
+			err := postWebhook(server.URL, payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postWebhook(), got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if got.Text != payload.Text || !reflect.DeepEqual(got.Allowed, payload.Allowed) {
+				t.Errorf("postWebhook(), got payload %+v, want %+v", got, payload)
+			}
+		})
+	}
+}
+
+func Test_PostWebhookUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	if err := postWebhook(server.URL, webhookPayload{}); err == nil || !strings.Contains(err.Error(), "connect") {
+		t.Errorf("postWebhook(), got error %v, want a connection error", err)
+	}
+}
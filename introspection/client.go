@@ -39,6 +39,11 @@ func New(cfg Config) *Introspector {
 	}
 }
 
+// Client returns the client that introspection queries are sent with
+func (c *Introspector) Client() *client.Client {
+	return c.gqlClient
+}
+
 func (c *Introspector) SetTargetURL(targetURL string) error {
 	if targetURL == c.Cfg.TargetUrl {
 		return nil
@@ -50,6 +55,7 @@ func (c *Introspector) SetTargetURL(targetURL string) error {
 	}
 
 	c.Cfg.TargetUrl = targetURL
+	c.gqlClient.Endpoint = targetURL
 
 	return nil
 }
@@ -72,6 +78,7 @@ func (c *Introspector) StartPolling(pollCallback func(*schema.Schema)) {
 			s, err := c.FetchSchema()
 			if err != nil {
 				log.Printf("error loading schema: %v", err)
+				continue
 			}
 			if pollCallback != nil {
 				pollCallback(s)
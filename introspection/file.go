@@ -0,0 +1,122 @@
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"github.com/TheLeeeo/gql-test-suite/utils"
+)
+
+// The file extensions of SDL files
+var sdlExtensions = []string{".graphql", ".graphqls", ".gql"}
+
+// LoadSchema loads a schema from a saved introspection result (a .json file),
+// an SDL file or a directory of SDL files, for when introspection is disabled on the target
+func LoadSchema(path string) (*schema.Schema, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error loading schema: %v", err)
+	}
+
+	if info.IsDir() {
+		return loadSDLDir(path)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return loadIntrospectionFile(path)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema file: %v", err)
+	}
+
+	s, err := sdl.ParseSources(sdl.Source{Name: path, Body: string(b)})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing schema file: %v", err)
+	}
+
+	return s, nil
+}
+
+// loadSDLDir parses the SDL files of the directory, in alphabetical order, as one schema
+func loadSDLDir(dir string) (*schema.Schema, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema directory: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		for _, sdlExt := range sdlExtensions {
+			if ext == sdlExt {
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no schema files found in %s", dir)
+	}
+
+	sources := make([]sdl.Source, len(names))
+	for i, name := range names {
+		path := filepath.Join(dir, name)
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading schema file: %v", err)
+		}
+
+		sources[i] = sdl.Source{Name: path, Body: string(b)}
+	}
+
+	s, err := sdl.ParseSources(sources...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing schema files: %v", err)
+	}
+
+	return s, nil
+}
+
+// loadIntrospectionFile loads the result of an introspection query,
+// either the full response or only its data
+func loadIntrospectionFile(path string) (*schema.Schema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading introspection file: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("error parsing introspection file: %v", err)
+	}
+
+	if data, ok := result["data"].(map[string]any); ok {
+		result = data
+	}
+
+	dataMap, ok := result["__schema"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("error parsing introspection file, no valid __schema field found")
+	}
+
+	sch := &schema.Schema{}
+	if err := utils.ParseMap(dataMap, sch); err != nil {
+		return nil, fmt.Errorf("error parsing schema: %v", err)
+	}
+
+	return sch, nil
+}
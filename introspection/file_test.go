@@ -0,0 +1,150 @@
+package introspection
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+const introspectionSchema = `{"__schema":{"queryType":{"name":"Query"},"types":[{"kind":"OBJECT","name":"Query","fields":[{"name":"me","args":[],"type":{"kind":"SCALAR","name":"String"}}]},{"kind":"SCALAR","name":"String"}]}}`
+
+func Test_LoadSchemaIntrospection(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "response",
+			content: `{"data":` + introspectionSchema + `}`,
+		},
+		{
+			name:    "data",
+			content: introspectionSchema,
+		},
+		{
+			name:    "malformed",
+			content: `{"data":{"__schema":`,
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			content: ``,
+			wantErr: true,
+		},
+		{
+			name:    "no schema",
+			content: `{"data":{"me":null}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "schema.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := LoadSchema(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadSchema(), got no error, want one")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadSchema(), got error %v", err)
+			}
+
+			if s.QueryType == nil || s.QueryType.Name != "Query" {
+				t.Errorf("LoadSchema(), got query type %v, want Query", s.QueryType)
+			}
+
+			if q := s.GetType("Query"); q == nil || len(q.Fields) != 1 || q.Fields[0].Name != "me" {
+				t.Errorf("LoadSchema(), got Query %v, want it with the field me", q)
+			}
+		})
+	}
+}
+
+func Test_LoadSchemaSDL(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		// The file to load, the directory is loaded if empty
+		file       string
+		wantFields []string
+		wantErr    bool
+	}{
+		{
+			name:       "file",
+			files:      map[string]string{"schema.graphql": "type Query { me: String }"},
+			file:       "schema.graphql",
+			wantFields: []string{"me"},
+		},
+		{
+			name: "directory",
+			files: map[string]string{
+				"a.graphql":  "type Query { me: String }",
+				"b.graphqls": "extend type Query { users: [User!]! }",
+				"c.gql":      "type User { id: ID! }",
+				"notes.txt":  "not a schema",
+			},
+			wantFields: []string{"me", "users"},
+		},
+		{
+			name:    "empty directory",
+			files:   map[string]string{"notes.txt": "not a schema"},
+			wantErr: true,
+		},
+		{
+			name: "invalid file in directory",
+			files: map[string]string{
+				"a.graphql": "type Query { me: String }",
+				"b.graphql": "type User {",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s, err := LoadSchema(filepath.Join(dir, tt.file))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadSchema(), got no error, want one")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadSchema(), got error %v", err)
+			}
+
+			q := s.GetType("Query")
+			if q == nil {
+				t.Fatalf("LoadSchema(), got no Query type")
+			}
+
+			var fields []string
+			for _, f := range q.Fields {
+				fields = append(fields, f.Name)
+			}
+
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("LoadSchema(), got Query fields %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
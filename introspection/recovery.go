@@ -0,0 +1,903 @@
+package introspection
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+	"golang.org/x/exp/slices"
+)
+
+// Recovering a schema when introspection is disabled, by probing it with names from a wordlist
+// and reading the validation errors of the probes. Servers built on graphql-js suggest similar names
+// for unknown fields, arguments and enum values, and name the types of fields and arguments in type errors.
+// The recovered schema is partial, as only the names found through the wordlist and suggestions are known
+
+type RecoveryConfig struct {
+	// The names to probe for fields, arguments, input fields and enum values, DefaultWordlist if empty
+	Wordlist []string
+
+	// The maximum number of probe requests to send, DefaultMaxProbes if 0
+	MaxProbes int
+
+	// The maximum number of probe requests per second, unlimited if 0
+	RateLimit float64
+}
+
+const (
+	DefaultMaxProbes = 5000
+
+	// The number of names probed per request, kept below the limit of validation errors of graphql-js
+	probeBatchSize = 40
+	// The number of names probed for the values of an enum, one request per name
+	maxEnumProbes = 20
+
+	// The number of error messages kept as examples of the leak
+	leakExamples = 5
+
+	// An invalid field selected on the root types, failing the validation of every probe
+	// to make sure that no probe is executed, as that could perform mutations
+	probeSentinel = "__probe"
+)
+
+// SuggestionLeak is the finding that the server leaks names of its schema through suggestions in error messages
+type SuggestionLeak struct {
+	// The number of distinct names that were suggested
+	Names int `json:"names"`
+	// Messages of errors containing suggestions
+	Examples []string `json:"examples"`
+}
+
+// Recovery is the result of recovering a schema
+type Recovery struct {
+	Schema *schema.Schema
+
+	// The recovered schema as SDL
+	SDL string
+
+	// The number of probe requests sent
+	Probes int
+
+	// Set if the server suggested names in its errors
+	Leak *SuggestionLeak
+}
+
+// The messages of graphql-js validation errors, covering the wording of versions 14 to 17
+var (
+	reCannotQuery      = regexp.MustCompile(`Cannot query field "(\w+)" on type "(\w+)"`)
+	reSubfields        = regexp.MustCompile(`Field "(\w+)" of type "([\w!\[\]]+)" must have a selection of subfields`)
+	reNoSubfields      = regexp.MustCompile(`Field "(\w+)" must not have a selection since type "([\w!\[\]]+)" has no subfields`)
+	reRequiredArg      = regexp.MustCompile(`Field "(\w+)" argument "(\w+)" of type "([\w!\[\]]+)" is required`)
+	reRequiredArgCoord = regexp.MustCompile(`Argument "\w+\.(\w+)\((\w+):\)" of type "([\w!\[\]]+)" is required`)
+	reUnknownArg       = regexp.MustCompile(`Unknown argument "(\w+)" on field "(?:\w+\.)?(\w+)"`)
+	reExpectedType     = regexp.MustCompile(`Expected (?:value of )?type "?([\w!\[\]]+)"?, found`)
+	reScalarCannot     = regexp.MustCompile(`^(String|Int|Float|Boolean|ID) cannot represent`)
+	reEnumCannot       = regexp.MustCompile(`Enum "(\w+)" cannot represent`)
+	reNotDefinedByType = regexp.MustCompile(`Field "(\w+)" is not defined by type "(\w+)"`)
+	reRequiredInput    = regexp.MustCompile(`Field "(\w+)\.(\w+)" of required type "([\w!\[\]]+)" was not provided`)
+	reEnumValue        = regexp.MustCompile(`Value "(\w+)" does not exist in "(\w+)" enum`)
+	reSuggestionPart   = regexp.MustCompile(`Did you mean (?:to use an inline fragment on |the enum value )?(.*)\?`)
+	reInlineFragment   = regexp.MustCompile(`Did you mean to use an inline fragment on (.*)\?`)
+	reQuotedName       = regexp.MustCompile(`"(\w+)"`)
+	reName             = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+)
+
+var builtinScalarNames = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// suggestedNames returns the names suggested by the message, if any
+func suggestedNames(message string) []string {
+	m := reSuggestionPart.FindStringSubmatch(message)
+	if m == nil {
+		return nil
+	}
+
+	var names []string
+	for _, q := range reQuotedName.FindAllStringSubmatch(m[1], -1) {
+		names = append(names, q[1])
+	}
+
+	return names
+}
+
+// baseTypeName removes the list and non null wrappers of a type reference, eg. [User!]! is User
+func baseTypeName(ref string) string {
+	return strings.Trim(ref, "[]!")
+}
+
+type recoveredField struct {
+	name string
+	// The type reference, eg. [User!]!
+	typeRef string
+
+	args []recoveredArg
+	// The names already probed as arguments
+	triedArgs map[string]bool
+}
+
+type recoveredArg struct {
+	name    string
+	typeRef string
+}
+
+type recoveredType struct {
+	name string
+	// The kind of the type, empty until an argument or input field type has been classified
+	kind schema.TypeKind
+
+	fields        []*recoveredField
+	inputFields   []recoveredArg
+	enumValues    []string
+	possibleTypes []string
+
+	// The names already probed on the type
+	tried map[string]bool
+
+	// Builds a query selecting the selection on an output type
+	selection func(sel string) string
+	// Builds a query using the value for an input type
+	value func(v string) string
+}
+
+func (t *recoveredType) hasField(name string) bool {
+	return slices.ContainsFunc(t.fields, func(f *recoveredField) bool { return f.name == name })
+}
+
+func (t *recoveredType) hasInputField(name string) bool {
+	return slices.ContainsFunc(t.inputFields, func(f recoveredArg) bool { return f.name == name })
+}
+
+type recoverer struct {
+	c *Introspector
+
+	words   []string
+	wordSet map[string]bool
+
+	types map[string]*recoveredType
+	// The names of the types in the order they were found
+	order []string
+
+	queryType    string
+	mutationType string
+
+	probes    int
+	maxProbes int
+
+	// The time between probes and when the last one was sent, keeping to the rate limit
+	interval  time.Duration
+	lastProbe time.Time
+
+	leakedNames map[string]bool
+	leaks       []string
+}
+
+// RecoverSchema reconstructs the schema from the validation errors of probe queries, for servers with introspection disabled
+func (c *Introspector) RecoverSchema(cfg RecoveryConfig) (*Recovery, error) {
+	if c.Cfg.TargetUrl == "" {
+		return nil, ErrNoTargetAddr
+	}
+
+	r := &recoverer{
+		c:           c,
+		wordSet:     make(map[string]bool),
+		types:       make(map[string]*recoveredType),
+		maxProbes:   cfg.MaxProbes,
+		leakedNames: make(map[string]bool),
+	}
+	if r.maxProbes == 0 {
+		r.maxProbes = DefaultMaxProbes
+	}
+	if cfg.RateLimit > 0 {
+		r.interval = time.Duration(float64(time.Second) / cfg.RateLimit)
+	}
+
+	wordlist := cfg.Wordlist
+	if len(wordlist) == 0 {
+		wordlist = DefaultWordlist
+	}
+	for _, w := range wordlist {
+		r.addWord(w)
+	}
+
+	if len(r.words) == 0 {
+		return nil, fmt.Errorf("the wordlist contains no valid names")
+	}
+
+	if err := r.findRoots(); err != nil {
+		return nil, err
+	}
+
+	// Every round probes the names that were not yet tried on every type, until no new names are found
+	for {
+		words := len(r.words)
+
+		for i := 0; i < len(r.order); i++ {
+			r.explore(r.types[r.order[i]])
+		}
+
+		if len(r.words) == words || r.probes >= r.maxProbes {
+			break
+		}
+	}
+
+	rec := &Recovery{
+		SDL:    r.sdl(),
+		Probes: r.probes,
+	}
+
+	if len(r.leakedNames) > 0 {
+		rec.Leak = &SuggestionLeak{
+			Names:    len(r.leakedNames),
+			Examples: r.leaks,
+		}
+	}
+
+	s, err := sdl.Parse(rec.SDL)
+	if err != nil {
+		return nil, fmt.Errorf("error building recovered schema: %v", err)
+	}
+	rec.Schema = s
+
+	return rec, nil
+}
+
+func (r *recoverer) addWord(w string) {
+	if r.wordSet[w] || !reName.MatchString(w) || strings.HasPrefix(w, "__") {
+		return
+	}
+
+	r.wordSet[w] = true
+	r.words = append(r.words, w)
+}
+
+// probe sends the query and returns the messages of its errors and the response
+func (r *recoverer) probe(query string) ([]string, *client.Response) {
+	if r.probes >= r.maxProbes {
+		return nil, nil
+	}
+	r.probes++
+	r.wait()
+
+	req := client.NewRequest(query, nil)
+	req.Headers = r.c.Cfg.Headers
+
+	resp, _ := r.c.gqlClient.Execute(req)
+	if resp == nil {
+		return nil, nil
+	}
+
+	messages := make([]string, len(resp.Errors))
+	for i, e := range resp.Errors {
+		messages[i] = e.Message
+		r.noteLeak(e.Message)
+	}
+
+	return messages, resp
+}
+
+// wait blocks until the rate limit allows the next probe to be sent
+func (r *recoverer) wait() {
+	if r.interval == 0 {
+		return
+	}
+
+	if d := time.Until(r.lastProbe.Add(r.interval)); d > 0 {
+		time.Sleep(d)
+	}
+	r.lastProbe = time.Now()
+}
+
+// noteLeak records the names suggested by the message.
+// The hint to select subfields of a field is not a leak, as it only repeats the name of the field
+func (r *recoverer) noteLeak(message string) {
+	if reSubfields.MatchString(message) {
+		return
+	}
+
+	names := suggestedNames(message)
+	if len(names) == 0 {
+		return
+	}
+
+	for _, n := range names {
+		r.leakedNames[n] = true
+	}
+
+	if len(r.leaks) < leakExamples && !slices.Contains(r.leaks, message) {
+		r.leaks = append(r.leaks, message)
+	}
+}
+
+// findRoots finds the names of the query and mutation types from the typename of the root operations
+func (r *recoverer) findRoots() error {
+	_, resp := r.probe("query { __typename }")
+	if resp == nil {
+		return fmt.Errorf("error probing the query type")
+	}
+
+	r.queryType = "Query"
+	if name, ok := resp.Data["__typename"].(string); ok {
+		r.queryType = name
+	}
+	r.addObject(r.queryType, func(sel string) string { return fmt.Sprintf("query { %s %s }", sel, probeSentinel) })
+
+	_, resp = r.probe("mutation { __typename }")
+	if resp != nil {
+		if name, ok := resp.Data["__typename"].(string); ok {
+			r.mutationType = name
+			r.addObject(name, func(sel string) string { return fmt.Sprintf("mutation { %s %s }", sel, probeSentinel) })
+		}
+	}
+
+	return nil
+}
+
+// addObject adds an output type with fields, keeping the first way found to select it
+func (r *recoverer) addObject(name string, selection func(string) string) *recoveredType {
+	if t, ok := r.types[name]; ok {
+		if t.kind == schema.ScalarTypeKind {
+			t.kind = schema.ObjectTypeKind
+		}
+		if t.selection == nil {
+			t.selection = selection
+		}
+		return t
+	}
+
+	t := &recoveredType{
+		name:      name,
+		kind:      schema.ObjectTypeKind,
+		tried:     make(map[string]bool),
+		selection: selection,
+	}
+	r.types[name] = t
+	r.order = append(r.order, name)
+
+	return t
+}
+
+// addLeaf adds a custom scalar or enum used as an output type
+func (r *recoverer) addLeaf(name string) {
+	if slices.Contains(builtinScalarNames, name) {
+		return
+	}
+
+	if _, ok := r.types[name]; ok {
+		return
+	}
+
+	r.types[name] = &recoveredType{
+		name:  name,
+		kind:  schema.ScalarTypeKind,
+		tried: make(map[string]bool),
+	}
+	r.order = append(r.order, name)
+}
+
+// addInput adds a type used as an argument or input field, its kind is found when it is explored
+func (r *recoverer) addInput(name string, value func(string) string) {
+	if slices.Contains(builtinScalarNames, name) {
+		return
+	}
+
+	if t, ok := r.types[name]; ok {
+		if t.value == nil {
+			t.value = value
+			// Custom leaf types found as output types may be enums
+			if t.kind == schema.ScalarTypeKind {
+				t.kind = ""
+			}
+		}
+		return
+	}
+
+	r.types[name] = &recoveredType{
+		name:  name,
+		tried: make(map[string]bool),
+		value: value,
+	}
+	r.order = append(r.order, name)
+}
+
+func (r *recoverer) explore(t *recoveredType) {
+	if t.kind == "" && t.value != nil {
+		r.classify(t)
+	}
+
+	switch t.kind {
+	case schema.ObjectTypeKind, schema.InterfaceTypeKind, schema.UnionTypeKind:
+		r.exploreFields(t)
+
+		for _, f := range t.fields {
+			r.exploreArgs(t, f)
+		}
+	case schema.InputObjectTypeKind:
+		r.exploreInputFields(t)
+	case schema.EnumTypeKind:
+		r.exploreEnumValues(t)
+	}
+}
+
+// untried returns the words not yet probed in the set, marking them as probed
+func (r *recoverer) untried(tried map[string]bool) []string {
+	var words []string
+	for _, w := range r.words {
+		if !tried[w] {
+			tried[w] = true
+			words = append(words, w)
+		}
+	}
+
+	return words
+}
+
+func batches(words []string) [][]string {
+	var b [][]string
+	for len(words) > probeBatchSize {
+		b = append(b, words[:probeBatchSize])
+		words = words[probeBatchSize:]
+	}
+	if len(words) > 0 {
+		b = append(b, words)
+	}
+
+	return b
+}
+
+// exploreFields selects the untried words on the type.
+// Words that are not reported as unknown fields are fields, as are the names suggested for the unknown ones
+func (r *recoverer) exploreFields(t *recoveredType) {
+	for _, batch := range batches(r.untried(t.tried)) {
+		messages, resp := r.probe(t.selection(strings.Join(batch, " ")))
+		if resp == nil {
+			return
+		}
+
+		unknown := make(map[string]bool)
+		var found []string
+
+		for _, m := range messages {
+			match := reCannotQuery.FindStringSubmatch(m)
+			if match == nil || match[2] != t.name {
+				continue
+			}
+			unknown[match[1]] = true
+
+			if inline := reInlineFragment.FindStringSubmatch(m); inline != nil {
+				for _, q := range reQuotedName.FindAllStringSubmatch(inline[1], -1) {
+					r.addPossibleType(t, q[1])
+				}
+				continue
+			}
+
+			found = append(found, suggestedNames(m)...)
+		}
+
+		// Without any unknown field the probe was not validated as expected, so its words are not trusted
+		delete(unknown, probeSentinel)
+		if len(unknown) == 0 {
+			continue
+		}
+
+		for _, w := range batch {
+			if !unknown[w] {
+				found = append(found, w)
+			}
+		}
+
+		for _, name := range found {
+			r.addField(t, name)
+		}
+	}
+}
+
+// addPossibleType makes the type abstract and adds an object type that it can be
+func (r *recoverer) addPossibleType(t *recoveredType, name string) {
+	if slices.Contains(t.possibleTypes, name) {
+		return
+	}
+
+	t.kind = schema.UnionTypeKind
+	t.possibleTypes = append(t.possibleTypes, name)
+
+	r.addObject(name, func(sel string) string {
+		return t.selection(fmt.Sprintf("... on %s { %s }", name, sel))
+	})
+}
+
+// addField finds the type and the required arguments of the field from the errors of selecting it
+func (r *recoverer) addField(t *recoveredType, name string) {
+	if t.hasField(name) || strings.HasPrefix(name, "__") {
+		return
+	}
+
+	f := &recoveredField{name: name, triedArgs: make(map[string]bool)}
+	composite := false
+
+	messages, _ := r.probe(t.selection(name))
+	for _, m := range messages {
+		if match := reSubfields.FindStringSubmatch(m); match != nil && match[1] == name {
+			f.typeRef = match[2]
+			composite = true
+		}
+	}
+	r.requiredArgs(f, messages)
+
+	if f.typeRef == "" {
+		messages, _ = r.probe(t.selection(fmt.Sprintf("%s { __typename }", name)))
+		for _, m := range messages {
+			if match := reNoSubfields.FindStringSubmatch(m); match != nil && match[1] == name {
+				f.typeRef = match[2]
+			}
+		}
+	}
+
+	// The type of the field is needed to select it, so fields without a known type are left out
+	if f.typeRef == "" {
+		return
+	}
+
+	t.fields = append(t.fields, f)
+	r.addWord(name)
+
+	base := baseTypeName(f.typeRef)
+	if composite {
+		r.addObject(base, func(sel string) string {
+			return t.selection(fmt.Sprintf("%s { %s }", name, sel))
+		})
+	} else {
+		r.addLeaf(base)
+	}
+}
+
+// requiredArgs adds the arguments reported as missing, along with their types
+func (r *recoverer) requiredArgs(f *recoveredField, messages []string) {
+	for _, m := range messages {
+		match := reRequiredArg.FindStringSubmatch(m)
+		if match == nil {
+			match = reRequiredArgCoord.FindStringSubmatch(m)
+		}
+		if match == nil || match[1] != f.name {
+			continue
+		}
+
+		f.triedArgs[match[2]] = true
+		if !slices.ContainsFunc(f.args, func(a recoveredArg) bool { return a.name == match[2] }) {
+			f.args = append(f.args, recoveredArg{name: match[2], typeRef: match[3]})
+		}
+	}
+}
+
+// fieldCall builds the selection of the field with the arguments
+func fieldCall(f *recoveredField, args string) string {
+	sel := ""
+	if !slices.Contains(builtinScalarNames, baseTypeName(f.typeRef)) {
+		sel = " { __typename }"
+	}
+
+	return fmt.Sprintf("%s(%s)%s", f.name, args, sel)
+}
+
+// exploreArgs passes the untried words as arguments to the field.
+// Words that are not reported as unknown arguments are arguments, as are the names suggested for the unknown ones
+func (r *recoverer) exploreArgs(t *recoveredType, f *recoveredField) {
+	var found []string
+
+	for _, batch := range batches(r.untried(f.triedArgs)) {
+		args := make([]string, len(batch))
+		for i, w := range batch {
+			args[i] = fmt.Sprintf("%s: 7", w)
+		}
+
+		messages, resp := r.probe(t.selection(fieldCall(f, strings.Join(args, ", "))))
+		if resp == nil {
+			return
+		}
+		r.requiredArgs(f, messages)
+
+		unknown := make(map[string]bool)
+		for _, m := range messages {
+			match := reUnknownArg.FindStringSubmatch(m)
+			if match == nil || match[2] != f.name {
+				continue
+			}
+
+			unknown[match[1]] = true
+			found = append(found, suggestedNames(m)...)
+		}
+
+		if len(unknown) == 0 {
+			continue
+		}
+
+		for _, w := range batch {
+			if !unknown[w] {
+				found = append(found, w)
+			}
+		}
+	}
+
+	for _, name := range found {
+		if slices.ContainsFunc(f.args, func(a recoveredArg) bool { return a.name == name }) {
+			continue
+		}
+
+		typeRef := r.valueType(func(v string) string {
+			return t.selection(fieldCall(f, fmt.Sprintf("%s: %s", name, v)))
+		})
+
+		f.args = append(f.args, recoveredArg{name: name, typeRef: typeRef})
+		r.addWord(name)
+	}
+
+	for _, a := range f.args {
+		arg := a.name
+		r.addInput(baseTypeName(a.typeRef), func(v string) string {
+			return t.selection(fieldCall(f, fmt.Sprintf("%s: %s", arg, v)))
+		})
+	}
+}
+
+// valueType finds the type of an input from the errors of passing it an integer and a string.
+// Inputs accepting both are assumed to be IDs
+func (r *recoverer) valueType(value func(v string) string) string {
+	for _, v := range []string{"7", `"x"`} {
+		messages, _ := r.probe(value(v))
+
+		for _, m := range messages {
+			if match := reExpectedType.FindStringSubmatch(m); match != nil {
+				return match[1]
+			}
+			if match := reScalarCannot.FindStringSubmatch(m); match != nil {
+				return match[1]
+			}
+			if match := reEnumCannot.FindStringSubmatch(m); match != nil {
+				return match[1]
+			}
+		}
+	}
+
+	return "ID"
+}
+
+// classify finds the kind of a type used as an input by passing it an object.
+// Input objects report the unknown fields, enums reject the object and the other types are assumed to be scalars
+func (r *recoverer) classify(t *recoveredType) {
+	messages, _ := r.probe(t.value(fmt.Sprintf("{%s: 7}", r.words[0])))
+
+	for _, m := range messages {
+		if match := reNotDefinedByType.FindStringSubmatch(m); match != nil && match[2] == t.name {
+			t.kind = schema.InputObjectTypeKind
+			return
+		}
+		if match := reRequiredInput.FindStringSubmatch(m); match != nil && match[1] == t.name {
+			t.kind = schema.InputObjectTypeKind
+			return
+		}
+		if match := reEnumCannot.FindStringSubmatch(m); match != nil && match[1] == t.name {
+			t.kind = schema.EnumTypeKind
+			return
+		}
+	}
+
+	messages, _ = r.probe(t.value(strings.ToUpper(r.words[0])))
+	for _, m := range messages {
+		if match := reEnumValue.FindStringSubmatch(m); match != nil && match[2] == t.name {
+			t.kind = schema.EnumTypeKind
+			return
+		}
+	}
+
+	t.kind = schema.ScalarTypeKind
+}
+
+// exploreInputFields passes the untried words as fields of the input object.
+// Words that are not reported as unknown fields are fields, as are the names suggested for the unknown ones
+func (r *recoverer) exploreInputFields(t *recoveredType) {
+	var found []string
+	required := make(map[string]string)
+
+	for _, batch := range batches(r.untried(t.tried)) {
+		fields := make([]string, len(batch))
+		for i, w := range batch {
+			fields[i] = fmt.Sprintf("%s: 7", w)
+		}
+
+		messages, resp := r.probe(t.value(fmt.Sprintf("{%s}", strings.Join(fields, ", "))))
+		if resp == nil {
+			return
+		}
+
+		unknown := make(map[string]bool)
+		for _, m := range messages {
+			if match := reRequiredInput.FindStringSubmatch(m); match != nil && match[1] == t.name {
+				required[match[2]] = match[3]
+				found = append(found, match[2])
+				continue
+			}
+
+			match := reNotDefinedByType.FindStringSubmatch(m)
+			if match == nil || match[2] != t.name {
+				continue
+			}
+
+			unknown[match[1]] = true
+			found = append(found, suggestedNames(m)...)
+		}
+
+		if len(unknown) == 0 {
+			continue
+		}
+
+		for _, w := range batch {
+			if !unknown[w] {
+				found = append(found, w)
+			}
+		}
+	}
+
+	for _, name := range found {
+		if t.hasInputField(name) {
+			continue
+		}
+
+		typeRef, ok := required[name]
+		if !ok {
+			field := name
+			typeRef = r.valueType(func(v string) string {
+				return t.value(fmt.Sprintf("{%s: %s}", field, v))
+			})
+		}
+
+		t.inputFields = append(t.inputFields, recoveredArg{name: name, typeRef: typeRef})
+		r.addWord(name)
+
+		field := name
+		r.addInput(baseTypeName(typeRef), func(v string) string {
+			return t.value(fmt.Sprintf("{%s: %s}", field, v))
+		})
+	}
+}
+
+// exploreEnumValues passes the untried words as values, one at a time as an argument takes a single value.
+// Words that are not reported as unknown values are values, as are the values suggested for the unknown ones
+func (r *recoverer) exploreEnumValues(t *recoveredType) {
+	probed := 0
+
+	// Words written like enum values are probed first, as only some words are probed per round
+	words := r.untried(t.tried)
+	slices.SortStableFunc(words, func(a, b string) int {
+		upperA, upperB := a == strings.ToUpper(a), b == strings.ToUpper(b)
+		switch {
+		case upperA && !upperB:
+			return -1
+		case !upperA && upperB:
+			return 1
+		}
+		return 0
+	})
+
+	for _, w := range words {
+		if probed >= maxEnumProbes {
+			return
+		}
+		probed++
+
+		value := strings.ToUpper(w)
+		if slices.Contains(t.enumValues, value) {
+			continue
+		}
+
+		messages, resp := r.probe(t.value(value))
+		if resp == nil {
+			return
+		}
+
+		found := []string{value}
+		for _, m := range messages {
+			invalid := reEnumValue.FindStringSubmatch(m)
+			if invalid == nil || invalid[2] != t.name {
+				// Older versions reject enum values as a value of the wrong type
+				invalid = reExpectedType.FindStringSubmatch(m)
+				if invalid != nil && baseTypeName(invalid[1]) != t.name {
+					invalid = nil
+				}
+			}
+
+			if invalid != nil {
+				found = suggestedNames(m)
+				break
+			}
+		}
+
+		for _, v := range found {
+			if !slices.Contains(t.enumValues, v) {
+				t.enumValues = append(t.enumValues, v)
+				r.addWord(v)
+			}
+		}
+	}
+}
+
+// sdl writes the recovered types as SDL.
+// Types without anything recovered are written as the simplest valid definition of their kind
+func (r *recoverer) sdl() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "schema {\n  query: %s\n", r.queryType)
+	if r.mutationType != "" {
+		fmt.Fprintf(&b, "  mutation: %s\n", r.mutationType)
+	}
+	b.WriteString("}\n")
+
+	// Abstract types with fields are interfaces, which their possible types implement
+	implements := make(map[string][]string)
+	for _, name := range r.order {
+		t := r.types[name]
+		if t.kind == schema.UnionTypeKind && len(t.fields) > 0 {
+			for _, p := range t.possibleTypes {
+				implements[p] = append(implements[p], t.name)
+			}
+		}
+	}
+
+	for _, name := range r.order {
+		t := r.types[name]
+		b.WriteString("\n")
+
+		switch {
+		case t.kind == schema.UnionTypeKind && len(t.fields) == 0:
+			fmt.Fprintf(&b, "union %s = %s\n", t.name, strings.Join(t.possibleTypes, " | "))
+		case t.kind == schema.UnionTypeKind, t.kind == schema.ObjectTypeKind:
+			keyword := "type"
+			if t.kind == schema.UnionTypeKind {
+				keyword = "interface"
+			}
+
+			fmt.Fprintf(&b, "%s %s", keyword, t.name)
+			if len(implements[t.name]) > 0 {
+				fmt.Fprintf(&b, " implements %s", strings.Join(implements[t.name], " & "))
+			}
+			if len(t.fields) > 0 {
+				b.WriteString(" {\n")
+				for _, f := range t.fields {
+					fmt.Fprintf(&b, "  %s%s: %s\n", f.name, sdlArgs(f.args), f.typeRef)
+				}
+				b.WriteString("}")
+			}
+			b.WriteString("\n")
+		case t.kind == schema.InputObjectTypeKind:
+			fmt.Fprintf(&b, "input %s", t.name)
+			if len(t.inputFields) > 0 {
+				b.WriteString(" {\n")
+				for _, f := range t.inputFields {
+					fmt.Fprintf(&b, "  %s: %s\n", f.name, f.typeRef)
+				}
+				b.WriteString("}")
+			}
+			b.WriteString("\n")
+		case t.kind == schema.EnumTypeKind && len(t.enumValues) > 0:
+			fmt.Fprintf(&b, "enum %s {\n  %s\n}\n", t.name, strings.Join(t.enumValues, "\n  "))
+		default:
+			fmt.Fprintf(&b, "scalar %s\n", t.name)
+		}
+	}
+
+	return b.String()
+}
+
+func sdlArgs(args []recoveredArg) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	s := make([]string, len(args))
+	for i, a := range args {
+		s[i] = fmt.Sprintf("%s: %s", a.name, a.typeRef)
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(s, ", "))
+}
@@ -0,0 +1,659 @@
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"golang.org/x/exp/slices"
+)
+
+func Test_SuggestedNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{
+			name:    "no suggestion",
+			message: `Cannot query field "foo" on type "Query".`,
+		},
+		{
+			name:    "single field",
+			message: `Cannot query field "usr" on type "Query". Did you mean "user"?`,
+			want:    []string{"user"},
+		},
+		{
+			name:    "several fields",
+			message: `Cannot query field "user" on type "Query". Did you mean "users", "userById", or "me"?`,
+			want:    []string{"users", "userById", "me"},
+		},
+		{
+			name:    "enum value",
+			message: `Value "ADMN" does not exist in "Role" enum. Did you mean the enum value "ADMIN"?`,
+			want:    []string{"ADMIN"},
+		},
+		{
+			name:    "inline fragment",
+			message: `Cannot query field "title" on type "SearchResult". Did you mean to use an inline fragment on "Post" or "Page"?`,
+			want:    []string{"Post", "Page"},
+		},
+		{
+			name:    "selection hint",
+			message: `Field "me" of type "User!" must have a selection of subfields. Did you mean "me { ... }"?`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestedNames(tt.message); !slices.Equal(got, tt.want) {
+				t.Errorf("suggestedNames(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ValueType(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "input object",
+			message: `Expected value of type "UserFilter", found 7.`,
+			want:    "UserFilter",
+		},
+		{
+			name:    "older wording",
+			message: `Expected type [Int!], found "x".`,
+			want:    "[Int!]",
+		},
+		{
+			name:    "scalar",
+			message: `String cannot represent a non string value: 7`,
+			want:    "String",
+		},
+		{
+			name:    "enum",
+			message: `Enum "Role" cannot represent non-enum value: 7.`,
+			want:    "Role",
+		},
+		{
+			name:    "unrelated",
+			message: `Unknown argument "foo" on field "Query.user".`,
+			want:    "ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]any{{"message": tt.message}}})
+			}))
+			defer server.Close()
+
+			r := &recoverer{
+				c:           New(Config{TargetUrl: server.URL}),
+				maxProbes:   DefaultMaxProbes,
+				leakedNames: make(map[string]bool),
+			}
+
+			got := r.valueType(func(v string) string { return fmt.Sprintf("query { user(id: %s) { id } }", v) })
+			if got != tt.want {
+				t.Errorf("valueType(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_BaseTypeName(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{
+			name: "named",
+			ref:  "User",
+			want: "User",
+		},
+		{
+			name: "non null",
+			ref:  "User!",
+			want: "User",
+		},
+		{
+			name: "list",
+			ref:  "[User]",
+			want: "User",
+		},
+		{
+			name: "non null list",
+			ref:  "[User!]!",
+			want: "User",
+		},
+		{
+			name: "nested list",
+			ref:  "[[Int]]",
+			want: "Int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := baseTypeName(tt.ref); got != tt.want {
+				t.Errorf("baseTypeName(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RecoverSchema(t *testing.T) {
+	server := newSuggestingServer(t)
+
+	c := New(Config{TargetUrl: server.URL})
+
+	rec, err := c.RecoverSchema(RecoveryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// UserFilter.nameContains is not in the wordlist and too far from its words to be suggested
+	want := `schema {
+  query: Query
+  mutation: Mutation
+}
+
+type Query {
+  search(term: String!): [SearchResult!]!
+  me: User!
+  user(id: ID!): User
+  users(filter: UserFilter, first: Int): [User!]!
+}
+
+type Mutation {
+  deleteUser(id: ID!): Boolean
+}
+
+union SearchResult = User | Post
+
+type User {
+  name: String
+  role: Role!
+  id: ID!
+}
+
+input UserFilter {
+  role: Role
+}
+
+type Post {
+  title: String!
+  id: ID!
+}
+
+enum Role {
+  ADMIN
+  USER
+  GUEST
+}
+`
+	if rec.SDL != want {
+		t.Errorf("RecoverSchema(), got SDL\n%s\nwant\n%s", rec.SDL, want)
+	}
+
+	if rec.Leak == nil {
+		t.Fatalf("RecoverSchema(), got no suggestion leak, want one")
+	}
+
+	if rec.Leak.Names != 15 {
+		t.Errorf("RecoverSchema(), got %d leaked names, want %d", rec.Leak.Names, 15)
+	}
+
+	wantExample := `Cannot query field "health" on type "Query". Did you mean "search"?`
+	if len(rec.Leak.Examples) != leakExamples || rec.Leak.Examples[0] != wantExample {
+		t.Errorf("RecoverSchema(), got examples %v, want %d starting with %s", rec.Leak.Examples, leakExamples, wantExample)
+	}
+}
+
+func Test_RecoverSchemaRateLimit(t *testing.T) {
+	server := newSuggestingServer(t)
+
+	c := New(Config{TargetUrl: server.URL})
+
+	const probes = 6
+	const rate = 50.0
+
+	start := time.Now()
+	rec, err := c.RecoverSchema(RecoveryConfig{MaxProbes: probes, RateLimit: rate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+
+	if rec.Probes != probes {
+		t.Fatalf("RecoverSchema(), got %d probes, want %d", rec.Probes, probes)
+	}
+
+	// The first probe is sent right away and every other after a full interval
+	if min := time.Duration(float64(probes-1) / rate * float64(time.Second)); elapsed < min {
+		t.Errorf("RecoverSchema(), took %v, want at least %v", elapsed, min)
+	}
+}
+
+// mockType is a type of the schema served by newSuggestingServer
+type mockType struct {
+	kind schema.TypeKind
+	// The fields of objects and input objects, by name
+	fields map[string]mockField
+	// The types that a union can be
+	possible []string
+	// The values of enums
+	values []string
+}
+
+type mockField struct {
+	typeRef string
+	// The types of the arguments, by name
+	args map[string]string
+}
+
+var mockScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// mockSchema is served with introspection disabled
+var mockSchema = map[string]mockType{
+	"Query": {kind: schema.ObjectTypeKind, fields: map[string]mockField{
+		"me":     {typeRef: "User!"},
+		"user":   {typeRef: "User", args: map[string]string{"id": "ID!"}},
+		"users":  {typeRef: "[User!]!", args: map[string]string{"filter": "UserFilter", "first": "Int"}},
+		"search": {typeRef: "[SearchResult!]!", args: map[string]string{"term": "String!"}},
+	}},
+	"Mutation": {kind: schema.ObjectTypeKind, fields: map[string]mockField{
+		"deleteUser": {typeRef: "Boolean", args: map[string]string{"id": "ID!"}},
+	}},
+	"User": {kind: schema.ObjectTypeKind, fields: map[string]mockField{
+		"id":   {typeRef: "ID!"},
+		"name": {typeRef: "String"},
+		"role": {typeRef: "Role!"},
+	}},
+	"Post": {kind: schema.ObjectTypeKind, fields: map[string]mockField{
+		"id":    {typeRef: "ID!"},
+		"title": {typeRef: "String!"},
+	}},
+	"SearchResult": {kind: schema.UnionTypeKind, possible: []string{"Post", "User"}},
+	"UserFilter": {kind: schema.InputObjectTypeKind, fields: map[string]mockField{
+		"role":         {typeRef: "Role"},
+		"nameContains": {typeRef: "String"},
+	}},
+	"Role": {kind: schema.EnumTypeKind, values: []string{"ADMIN", "USER", "GUEST"}},
+}
+
+// newSuggestingServer starts a server validating queries against mockSchema with the messages and suggestions of graphql-js 16
+func newSuggestingServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload client.Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var messages []string
+		operation := "Query"
+
+		tokens := mockTokens.FindAllString(payload.Query, -1)
+		if len(tokens) > 0 && (tokens[0] == "query" || tokens[0] == "mutation") {
+			operation = strings.ToUpper(tokens[0][:1]) + tokens[0][1:]
+			tokens = tokens[1:]
+		}
+
+		p := &mockParser{tokens: tokens}
+		selection := p.selection()
+		if p.err {
+			messages = append(messages, "Syntax Error")
+		} else {
+			messages = mockValidate(selection, operation)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if len(messages) == 0 {
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"__typename": operation}})
+			return
+		}
+
+		errs := make([]map[string]any, len(messages))
+		for i, m := range messages {
+			errs[i] = map[string]any{"message": m, "extensions": map[string]any{"code": "GRAPHQL_VALIDATION_FAILED"}}
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{"errors": errs})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+var mockTokens = regexp.MustCompile(`\.\.\.|[{}():\[\]]|"[^"]*"|-?\d+|[_A-Za-z]\w*`)
+
+type mockSelection struct {
+	name string
+	args map[string]mockValue
+	// The subfields, nil for leaves
+	sub []mockSelection
+	// The type of an inline fragment
+	on string
+}
+
+type mockValue struct {
+	kind string // "object", "string", "int" or "enum"
+	raw  string
+	// The fields of an object, in the order they were written
+	fields []string
+	values map[string]mockValue
+}
+
+func (v mockValue) String() string {
+	if v.kind != "object" {
+		return v.raw
+	}
+
+	parts := make([]string, len(v.fields))
+	for i, f := range v.fields {
+		parts[i] = fmt.Sprintf("%s: %s", f, v.values[f])
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+type mockParser struct {
+	tokens []string
+	pos    int
+	err    bool
+}
+
+func (p *mockParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *mockParser) next() string {
+	t := p.peek()
+	if t == "" {
+		p.err = true
+	}
+	p.pos++
+
+	return t
+}
+
+func (p *mockParser) expect(token string) {
+	if p.next() != token {
+		p.err = true
+	}
+}
+
+func (p *mockParser) selection() []mockSelection {
+	p.expect("{")
+
+	var sel []mockSelection
+	for !p.err && p.peek() != "}" {
+		if p.peek() == "..." {
+			p.next()
+			p.expect("on")
+			on := p.next()
+			sel = append(sel, mockSelection{on: on, sub: p.selection()})
+			continue
+		}
+
+		s := mockSelection{name: p.next(), args: make(map[string]mockValue)}
+		if p.peek() == "(" {
+			p.next()
+			for !p.err && p.peek() != ")" {
+				name := p.next()
+				p.expect(":")
+				s.args[name] = p.value()
+			}
+			p.next()
+		}
+		if p.peek() == "{" {
+			s.sub = p.selection()
+		}
+
+		sel = append(sel, s)
+	}
+	p.next()
+
+	return sel
+}
+
+func (p *mockParser) value() mockValue {
+	t := p.next()
+
+	switch {
+	case t == "{":
+		v := mockValue{kind: "object", values: make(map[string]mockValue)}
+		for !p.err && p.peek() != "}" {
+			name := p.next()
+			p.expect(":")
+			v.fields = append(v.fields, name)
+			v.values[name] = p.value()
+		}
+		p.next()
+		return v
+	case strings.HasPrefix(t, `"`):
+		return mockValue{kind: "string", raw: t}
+	case regexp.MustCompile(`^-?\d`).MatchString(t):
+		return mockValue{kind: "int", raw: t}
+	}
+
+	return mockValue{kind: "enum", raw: t}
+}
+
+// mockDistance is the lexical distance that graphql-js suggests names by
+func mockDistance(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a == b {
+		return 1
+	}
+
+	d := make([]int, len(b)+1)
+	for j := range d {
+		d[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := d[0]
+		d[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			prev, d[j] = d[j], min(d[j]+1, d[j-1]+1, prev+cost)
+		}
+	}
+
+	return d[len(b)]
+}
+
+// mockSuggest returns the options close enough to the input to be suggested, closest first
+func mockSuggest(input string, options []string) []string {
+	threshold := len(input)*4/10 + 1
+
+	var names []string
+	for _, o := range options {
+		if mockDistance(input, o) <= threshold {
+			names = append(names, o)
+		}
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		di, dj := mockDistance(input, names[i]), mockDistance(input, names[j])
+		if di != dj {
+			return di < dj
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+func mockDidYouMean(names []string, prefix string) string {
+	if len(names) > 5 {
+		names = names[:5]
+	}
+
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+
+	switch len(quoted) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf(" Did you mean %s%s?", prefix, quoted[0])
+	case 2:
+		return fmt.Sprintf(" Did you mean %s%s or %s?", prefix, quoted[0], quoted[1])
+	}
+
+	return fmt.Sprintf(" Did you mean %s%s, or %s?", prefix, strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func mockValidate(sel []mockSelection, typeName string) []string {
+	t := mockSchema[typeName]
+
+	var messages []string
+	for _, s := range sel {
+		if s.on != "" {
+			messages = append(messages, mockValidate(s.sub, s.on)...)
+			continue
+		}
+
+		if s.name == "__typename" {
+			continue
+		}
+
+		f, ok := t.fields[s.name]
+		if !ok {
+			if t.kind == schema.UnionTypeKind {
+				var types []string
+				for _, p := range t.possible {
+					if _, ok := mockSchema[p].fields[s.name]; ok {
+						types = append(types, p)
+					}
+				}
+				messages = append(messages, fmt.Sprintf(`Cannot query field "%s" on type "%s".%s`, s.name, typeName, mockDidYouMean(types, "to use an inline fragment on ")))
+			} else {
+				messages = append(messages, fmt.Sprintf(`Cannot query field "%s" on type "%s".%s`, s.name, typeName, mockDidYouMean(mockSuggest(s.name, sortedKeys(t.fields)), "")))
+			}
+			continue
+		}
+
+		for _, name := range sortedKeys(s.args) {
+			argType, ok := f.args[name]
+			if !ok {
+				messages = append(messages, fmt.Sprintf(`Unknown argument "%s" on field "%s.%s".%s`, name, typeName, s.name, mockDidYouMean(mockSuggest(name, sortedKeys(f.args)), "")))
+				continue
+			}
+			messages = append(messages, mockCheckValue(s.args[name], argType)...)
+		}
+
+		for _, name := range sortedKeys(f.args) {
+			if _, ok := s.args[name]; !ok && strings.HasSuffix(f.args[name], "!") {
+				messages = append(messages, fmt.Sprintf(`Field "%s" argument "%s" of type "%s" is required, but it was not provided.`, s.name, name, f.args[name]))
+			}
+		}
+
+		base := strings.Trim(f.typeRef, "[]!")
+		leaf := slices.Contains(mockScalars, base) || mockSchema[base].kind == schema.EnumTypeKind
+
+		switch {
+		case leaf && s.sub != nil:
+			messages = append(messages, fmt.Sprintf(`Field "%s" must not have a selection since type "%s" has no subfields.`, s.name, f.typeRef))
+		case !leaf && s.sub == nil:
+			messages = append(messages, fmt.Sprintf(`Field "%s" of type "%s" must have a selection of subfields. Did you mean "%s { ... }"?`, s.name, f.typeRef, s.name))
+		case !leaf:
+			messages = append(messages, mockValidate(s.sub, base)...)
+		}
+	}
+
+	return messages
+}
+
+func mockCheckValue(v mockValue, typeRef string) []string {
+	typeRef = strings.TrimSuffix(typeRef, "!")
+	if strings.HasPrefix(typeRef, "[") {
+		return mockCheckValue(v, typeRef[1:len(typeRef)-1])
+	}
+
+	if slices.Contains(mockScalars, typeRef) {
+		switch {
+		case typeRef == "ID" && (v.kind == "int" || v.kind == "string"),
+			typeRef == "String" && v.kind == "string",
+			(typeRef == "Int" || typeRef == "Float") && v.kind == "int":
+			return nil
+		case typeRef == "String":
+			return []string{fmt.Sprintf("String cannot represent a non string value: %s", v)}
+		case typeRef == "Int" || typeRef == "Float":
+			return []string{fmt.Sprintf("%s cannot represent non-integer value: %s", typeRef, v)}
+		}
+		return []string{fmt.Sprintf("%s cannot represent a non %s value: %s", typeRef, strings.ToLower(typeRef), v)}
+	}
+
+	t := mockSchema[typeRef]
+	if t.kind == schema.EnumTypeKind {
+		if v.kind != "enum" {
+			return []string{fmt.Sprintf(`Enum "%s" cannot represent non-enum value: %s.`, typeRef, v)}
+		}
+		if !slices.Contains(t.values, v.raw) {
+			return []string{fmt.Sprintf(`Value "%s" does not exist in "%s" enum.%s`, v.raw, typeRef, mockDidYouMean(mockSuggest(v.raw, t.values), "the enum value "))}
+		}
+		return nil
+	}
+
+	if v.kind != "object" {
+		return []string{fmt.Sprintf(`Expected value of type "%s", found %s.`, typeRef, v)}
+	}
+
+	var messages []string
+	for _, name := range sortedKeys(t.fields) {
+		if _, ok := v.values[name]; !ok && strings.HasSuffix(t.fields[name].typeRef, "!") {
+			messages = append(messages, fmt.Sprintf(`Field "%s.%s" of required type "%s" was not provided.`, typeRef, name, t.fields[name].typeRef))
+		}
+	}
+	for _, name := range v.fields {
+		f, ok := t.fields[name]
+		if !ok {
+			messages = append(messages, fmt.Sprintf(`Field "%s" is not defined by type "%s".%s`, name, typeRef, mockDidYouMean(mockSuggest(name, sortedKeys(t.fields)), "")))
+			continue
+		}
+		messages = append(messages, mockCheckValue(v.values[name], f.typeRef)...)
+	}
+
+	return messages
+}
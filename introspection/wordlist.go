@@ -0,0 +1,62 @@
+package introspection
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultWordlist holds common names of fields, arguments and enum values in graphql schemas.
+// Names close to the ones in the schema are enough for servers that suggest similar names
+var DefaultWordlist = []string{
+	// Root fields
+	"me", "viewer", "node", "nodes", "user", "users", "account", "accounts", "profile", "profiles",
+	"admin", "admins", "search", "login", "logout", "register", "signup", "signin", "refresh", "session",
+	"settings", "config", "configuration", "health", "status", "version", "info", "stats", "metrics",
+	"post", "posts", "comment", "comments", "article", "articles", "page", "pages", "file", "files",
+	"upload", "uploads", "image", "images", "message", "messages", "notification", "notifications",
+	"order", "orders", "product", "products", "item", "items", "cart", "payment", "payments",
+	"invoice", "invoices", "customer", "customers", "organization", "organizations", "team", "teams",
+	"project", "projects", "task", "tasks", "event", "events", "group", "groups", "role", "roles",
+	"permission", "permissions", "token", "tokens", "key", "keys", "secret", "secrets", "log", "logs",
+	"report", "reports", "tag", "tags", "category", "categories", "address", "addresses",
+	"create", "update", "delete", "remove", "add", "set", "reset", "send", "verify", "invite",
+	"createUser", "updateUser", "deleteUser", "createPost", "updatePost", "deletePost",
+	"changePassword", "resetPassword", "forgotPassword", "updateProfile",
+
+	// Object fields
+	"id", "uuid", "name", "title", "description", "type", "kind", "email", "username", "password",
+	"firstName", "lastName", "fullName", "phone", "avatar", "url", "link", "slug", "body", "content",
+	"text", "value", "data", "count", "total", "totalCount", "createdAt", "updatedAt", "deletedAt",
+	"owner", "author", "creator", "parent", "children", "friends", "followers", "members",
+	"edges", "cursor", "pageInfo", "hasNextPage", "hasPreviousPage", "startCursor", "endCursor",
+	"success", "error", "errors", "code", "result", "results", "enabled", "active", "isAdmin",
+
+	// Arguments and input fields
+	"input", "filter", "where", "query", "q", "term", "first", "last", "after", "before", "limit",
+	"offset", "skip", "take", "sort", "orderBy", "direction", "ids", "userId", "from", "to",
+
+	// Enum values
+	"ASC", "DESC", "ACTIVE", "INACTIVE", "PENDING", "ADMIN", "USER", "GUEST", "OWNER", "MEMBER",
+	"PUBLIC", "PRIVATE", "DRAFT", "PUBLISHED", "OPEN", "CLOSED",
+}
+
+// LoadWordlist reads a wordlist file with one name per line, skipping empty lines and lines starting with #
+func LoadWordlist(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading wordlist: %v", err)
+	}
+
+	var words []string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words = append(words, line)
+	}
+
+	return words, nil
+}
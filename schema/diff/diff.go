@@ -0,0 +1,423 @@
+// Package diff finds the changes between two versions of a schema and classifies how they affect clients
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"golang.org/x/exp/slices"
+)
+
+// Severity is how a change affects existing clients
+type Severity string
+
+const (
+	// Existing operations may stop working
+	Breaking Severity = "BREAKING"
+	// Existing operations keep working but may behave differently, eg. return an enum value clients do not know
+	Dangerous Severity = "DANGEROUS"
+	// Existing operations are not affected
+	Safe Severity = "SAFE"
+)
+
+type ChangeKind string
+
+const (
+	TypeAdded       ChangeKind = "TYPE_ADDED"
+	TypeRemoved     ChangeKind = "TYPE_REMOVED"
+	TypeKindChanged ChangeKind = "TYPE_KIND_CHANGED"
+
+	RootTypeChanged ChangeKind = "ROOT_TYPE_CHANGED"
+
+	FieldAdded              ChangeKind = "FIELD_ADDED"
+	FieldRemoved            ChangeKind = "FIELD_REMOVED"
+	FieldTypeChanged        ChangeKind = "FIELD_TYPE_CHANGED"
+	FieldDeprecated         ChangeKind = "FIELD_DEPRECATED"
+	FieldDeprecationRemoved ChangeKind = "FIELD_DEPRECATION_REMOVED"
+
+	ArgumentAdded         ChangeKind = "ARGUMENT_ADDED"
+	ArgumentRemoved       ChangeKind = "ARGUMENT_REMOVED"
+	ArgumentTypeChanged   ChangeKind = "ARGUMENT_TYPE_CHANGED"
+	ArgumentDefaultChange ChangeKind = "ARGUMENT_DEFAULT_CHANGED"
+
+	InputFieldAdded         ChangeKind = "INPUT_FIELD_ADDED"
+	InputFieldRemoved       ChangeKind = "INPUT_FIELD_REMOVED"
+	InputFieldTypeChanged   ChangeKind = "INPUT_FIELD_TYPE_CHANGED"
+	InputFieldDefaultChange ChangeKind = "INPUT_FIELD_DEFAULT_CHANGED"
+
+	EnumValueAdded      ChangeKind = "ENUM_VALUE_ADDED"
+	EnumValueRemoved    ChangeKind = "ENUM_VALUE_REMOVED"
+	EnumValueDeprecated ChangeKind = "ENUM_VALUE_DEPRECATED"
+
+	UnionMemberAdded   ChangeKind = "UNION_MEMBER_ADDED"
+	UnionMemberRemoved ChangeKind = "UNION_MEMBER_REMOVED"
+
+	InterfaceAdded   ChangeKind = "INTERFACE_ADDED"
+	InterfaceRemoved ChangeKind = "INTERFACE_REMOVED"
+
+	DirectiveAdded              ChangeKind = "DIRECTIVE_ADDED"
+	DirectiveRemoved            ChangeKind = "DIRECTIVE_REMOVED"
+	DirectiveLocationAdded      ChangeKind = "DIRECTIVE_LOCATION_ADDED"
+	DirectiveLocationRemoved    ChangeKind = "DIRECTIVE_LOCATION_REMOVED"
+	DirectiveRepeatableRemoved  ChangeKind = "DIRECTIVE_REPEATABLE_REMOVED"
+	DirectiveArgumentAdded      ChangeKind = "DIRECTIVE_ARGUMENT_ADDED"
+	DirectiveArgumentRemoved    ChangeKind = "DIRECTIVE_ARGUMENT_REMOVED"
+	DirectiveArgumentTypeChange ChangeKind = "DIRECTIVE_ARGUMENT_TYPE_CHANGED"
+)
+
+// Change is a single difference between two schemas
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Severity Severity   `json:"severity"`
+	// The schema coordinate of what changed, eg. "User.name", "Query.users(first:)" or "@auth"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Compare returns the changes from the old to the new schema, sorted by path
+func Compare(old *schema.Schema, new *schema.Schema) []Change {
+	d := &differ{changes: []Change{}}
+
+	d.compareRoots(old, new)
+	d.compareTypes(old.Types, new.Types)
+	d.compareDirectives(old.Directives, new.Directives)
+
+	slices.SortStableFunc(d.changes, func(a, b Change) int { return strings.Compare(a.Path, b.Path) })
+
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, severity Severity, path string, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Kind:     kind,
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *differ) compareRoots(old *schema.Schema, new *schema.Schema) {
+	roots := []struct {
+		op       string
+		old, new *schema.Type
+	}{
+		{"query", old.QueryType, new.QueryType},
+		{"mutation", old.MutationType, new.MutationType},
+		{"subscription", old.SubscriptionType, new.SubscriptionType},
+	}
+
+	for _, r := range roots {
+		oldName, newName := typeName(r.old), typeName(r.new)
+		if oldName == newName {
+			continue
+		}
+
+		severity := Breaking
+		if oldName == "" {
+			severity = Safe
+		}
+
+		d.add(RootTypeChanged, severity, "schema."+r.op, "The %s root type changed from %q to %q", r.op, oldName, newName)
+	}
+}
+
+func typeName(t *schema.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Name
+}
+
+func (d *differ) compareTypes(oldTypes []schema.Type, newTypes []schema.Type) {
+	newByName := make(map[string]schema.Type)
+	for _, t := range newTypes {
+		newByName[t.Name] = t
+	}
+	oldByName := make(map[string]schema.Type)
+	for _, t := range oldTypes {
+		oldByName[t.Name] = t
+	}
+
+	for _, old := range oldTypes {
+		if strings.HasPrefix(old.Name, "__") {
+			continue
+		}
+
+		new, ok := newByName[old.Name]
+		if !ok {
+			d.add(TypeRemoved, Breaking, old.Name, "Type %s was removed", old.Name)
+			continue
+		}
+
+		if old.Kind != new.Kind {
+			d.add(TypeKindChanged, Breaking, old.Name, "Type %s changed from %s to %s", old.Name, old.Kind, new.Kind)
+			continue
+		}
+
+		switch old.Kind {
+		case schema.ObjectTypeKind, schema.InterfaceTypeKind:
+			d.compareFields(old, new)
+			d.compareMembers(old.Name, old.Interfaces, new.Interfaces, InterfaceAdded, InterfaceRemoved, "Interface")
+		case schema.UnionTypeKind:
+			d.compareMembers(old.Name, old.PossibleTypes, new.PossibleTypes, UnionMemberAdded, UnionMemberRemoved, "Member")
+		case schema.EnumTypeKind:
+			d.compareEnumValues(old, new)
+		case schema.InputObjectTypeKind:
+			d.compareInputFields(old, new)
+		}
+	}
+
+	for _, new := range newTypes {
+		if _, ok := oldByName[new.Name]; !ok && !strings.HasPrefix(new.Name, "__") {
+			d.add(TypeAdded, Safe, new.Name, "Type %s was added", new.Name)
+		}
+	}
+}
+
+func (d *differ) compareFields(old schema.Type, new schema.Type) {
+	for _, of := range old.Fields {
+		path := old.Name + "." + of.Name
+
+		i := slices.IndexFunc(new.Fields, func(f schema.Field) bool { return f.Name == of.Name })
+		if i == -1 {
+			severity := Breaking
+			if of.IsDeprecated {
+				severity = Dangerous
+			}
+
+			d.add(FieldRemoved, severity, path, "Field %s was removed", path)
+			continue
+		}
+		nf := new.Fields[i]
+
+		if !isSafeOutputChange(of.Type, nf.Type) {
+			d.add(FieldTypeChanged, Breaking, path, "Field %s changed type from %s to %s", path, of.Type.String(), nf.Type.String())
+		} else if of.Type.String() != nf.Type.String() {
+			d.add(FieldTypeChanged, Safe, path, "Field %s changed type from %s to %s", path, of.Type.String(), nf.Type.String())
+		}
+
+		if !of.IsDeprecated && nf.IsDeprecated {
+			d.add(FieldDeprecated, Safe, path, "Field %s was deprecated", path)
+		}
+		if of.IsDeprecated && !nf.IsDeprecated {
+			d.add(FieldDeprecationRemoved, Safe, path, "Field %s is no longer deprecated", path)
+		}
+
+		d.compareArgs(path, of.Args, nf.Args, ArgumentAdded, ArgumentRemoved, ArgumentTypeChanged, ArgumentDefaultChange)
+	}
+
+	for _, nf := range new.Fields {
+		if !slices.ContainsFunc(old.Fields, func(f schema.Field) bool { return f.Name == nf.Name }) {
+			path := old.Name + "." + nf.Name
+			d.add(FieldAdded, Safe, path, "Field %s was added", path)
+		}
+	}
+}
+
+// compareArgs compares the arguments of a field or directive, or the fields of an input type
+func (d *differ) compareArgs(parent string, old []schema.InputValue, new []schema.InputValue, added, removed, typeChanged, defaultChanged ChangeKind) {
+	noun := "Argument"
+	path := func(name string) string {
+		return fmt.Sprintf("%s(%s:)", parent, name)
+	}
+
+	if added == InputFieldAdded {
+		noun = "Input field"
+		path = func(name string) string {
+			return parent + "." + name
+		}
+	}
+
+	for _, ov := range old {
+		i := slices.IndexFunc(new, func(v schema.InputValue) bool { return v.Name == ov.Name })
+		if i == -1 {
+			d.add(removed, Breaking, path(ov.Name), "%s %s was removed", noun, path(ov.Name))
+			continue
+		}
+		nv := new[i]
+
+		if !isSafeInputChange(ov.Type, nv.Type) {
+			d.add(typeChanged, Breaking, path(ov.Name), "%s %s changed type from %s to %s", noun, path(ov.Name), ov.Type.String(), nv.Type.String())
+		} else if ov.Type.String() != nv.Type.String() {
+			d.add(typeChanged, Safe, path(ov.Name), "%s %s changed type from %s to %s", noun, path(ov.Name), ov.Type.String(), nv.Type.String())
+		}
+
+		if defaultChanged != "" && ov.DefaultValue != nv.DefaultValue {
+			d.add(defaultChanged, Dangerous, path(ov.Name), "%s %s changed default value from %q to %q", noun, path(ov.Name), ov.DefaultValue, nv.DefaultValue)
+		}
+	}
+
+	for _, nv := range new {
+		if slices.ContainsFunc(old, func(v schema.InputValue) bool { return v.Name == nv.Name }) {
+			continue
+		}
+
+		if nv.Type != nil && nv.Type.Kind == schema.NonNullTypeKind && nv.DefaultValue == "" {
+			d.add(added, Breaking, path(nv.Name), "Required %s %s was added", strings.ToLower(noun), path(nv.Name))
+		} else {
+			d.add(added, Dangerous, path(nv.Name), "Optional %s %s was added", strings.ToLower(noun), path(nv.Name))
+		}
+	}
+}
+
+func (d *differ) compareInputFields(old schema.Type, new schema.Type) {
+	d.compareArgs(old.Name, old.InputFields, new.InputFields, InputFieldAdded, InputFieldRemoved, InputFieldTypeChanged, InputFieldDefaultChange)
+}
+
+func (d *differ) compareEnumValues(old schema.Type, new schema.Type) {
+	for _, ov := range old.EnumValues {
+		path := old.Name + "." + ov.Name
+
+		i := slices.IndexFunc(new.EnumValues, func(v schema.EnumValue) bool { return v.Name == ov.Name })
+		if i == -1 {
+			d.add(EnumValueRemoved, Breaking, path, "Enum value %s was removed", path)
+			continue
+		}
+
+		if !ov.IsDeprecated && new.EnumValues[i].IsDeprecated {
+			d.add(EnumValueDeprecated, Safe, path, "Enum value %s was deprecated", path)
+		}
+	}
+
+	// Clients may not handle values they do not know when they are returned
+	for _, nv := range new.EnumValues {
+		if !slices.ContainsFunc(old.EnumValues, func(v schema.EnumValue) bool { return v.Name == nv.Name }) {
+			path := old.Name + "." + nv.Name
+			d.add(EnumValueAdded, Dangerous, path, "Enum value %s was added", path)
+		}
+	}
+}
+
+// compareMembers compares the members of a union or the interfaces implemented by a type
+func (d *differ) compareMembers(name string, old []schema.Type, new []schema.Type, added, removed ChangeKind, noun string) {
+	for _, o := range old {
+		if !slices.ContainsFunc(new, func(t schema.Type) bool { return t.Name == o.Name }) {
+			d.add(removed, Breaking, name, "%s %s was removed from %s", noun, o.Name, name)
+		}
+	}
+
+	for _, n := range new {
+		if !slices.ContainsFunc(old, func(t schema.Type) bool { return t.Name == n.Name }) {
+			d.add(added, Dangerous, name, "%s %s was added to %s", noun, n.Name, name)
+		}
+	}
+}
+
+func (d *differ) compareDirectives(old []schema.Directive, new []schema.Directive) {
+	for _, od := range old {
+		path := "@" + od.Name
+
+		i := slices.IndexFunc(new, func(dir schema.Directive) bool { return dir.Name == od.Name })
+		if i == -1 {
+			d.add(DirectiveRemoved, Breaking, path, "Directive %s was removed", path)
+			continue
+		}
+		nd := new[i]
+
+		for _, l := range od.Locations {
+			if !slices.Contains(nd.Locations, l) {
+				d.add(DirectiveLocationRemoved, Breaking, path, "Location %s was removed from directive %s", l, path)
+			}
+		}
+		for _, l := range nd.Locations {
+			if !slices.Contains(od.Locations, l) {
+				d.add(DirectiveLocationAdded, Safe, path, "Location %s was added to directive %s", l, path)
+			}
+		}
+
+		if od.IsRepeatable && !nd.IsRepeatable {
+			d.add(DirectiveRepeatableRemoved, Breaking, path, "Directive %s is no longer repeatable", path)
+		}
+
+		d.compareArgs(path, od.Args, nd.Args, DirectiveArgumentAdded, DirectiveArgumentRemoved, DirectiveArgumentTypeChange, "")
+	}
+
+	for _, nd := range new {
+		if !slices.ContainsFunc(old, func(dir schema.Directive) bool { return dir.Name == nd.Name }) {
+			d.add(DirectiveAdded, Safe, "@"+nd.Name, "Directive @%s was added", nd.Name)
+		}
+	}
+}
+
+// isSafeOutputChange reports whether clients reading the old type can read the new one,
+// which is the case if the new type is the same or has stricter nullability
+func isSafeOutputChange(old *schema.Type, new *schema.Type) bool {
+	if old == nil || new == nil {
+		return old == new
+	}
+
+	switch {
+	case old.Kind == schema.NonNullTypeKind:
+		return new.Kind == schema.NonNullTypeKind && isSafeOutputChange(old.OfType, new.OfType)
+	case new.Kind == schema.NonNullTypeKind:
+		return isSafeOutputChange(old, new.OfType)
+	case old.Kind == schema.ListTypeKind:
+		return new.Kind == schema.ListTypeKind && isSafeOutputChange(old.OfType, new.OfType)
+	default:
+		return new.Kind != schema.ListTypeKind && old.Name == new.Name
+	}
+}
+
+// isSafeInputChange reports whether values sent for the old type are valid for the new one,
+// which is the case if the new type is the same or has looser nullability
+func isSafeInputChange(old *schema.Type, new *schema.Type) bool {
+	if old == nil || new == nil {
+		return old == new
+	}
+
+	switch {
+	case new.Kind == schema.NonNullTypeKind:
+		return old.Kind == schema.NonNullTypeKind && isSafeInputChange(old.OfType, new.OfType)
+	case old.Kind == schema.NonNullTypeKind:
+		return isSafeInputChange(old.OfType, new)
+	case old.Kind == schema.ListTypeKind:
+		return new.Kind == schema.ListTypeKind && isSafeInputChange(old.OfType, new.OfType)
+	default:
+		return new.Kind != schema.ListTypeKind && old.Name == new.Name
+	}
+}
+
+// Count returns the number of changes of each severity
+func Count(changes []Change) map[Severity]int {
+	counts := make(map[Severity]int)
+	for _, c := range changes {
+		counts[c.Severity]++
+	}
+
+	return counts
+}
+
+func HasBreaking(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(c Change) bool { return c.Severity == Breaking })
+}
+
+// Summary describes the number of changes per severity, eg. "2 breaking, 0 dangerous, 5 safe"
+func Summary(changes []Change) string {
+	counts := Count(changes)
+	return fmt.Sprintf("%d breaking, %d dangerous, %d safe", counts[Breaking], counts[Dangerous], counts[Safe])
+}
+
+// Print writes the changes grouped by severity, the most severe first
+func Print(w io.Writer, changes []Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+
+	for _, severity := range []Severity{Breaking, Dangerous, Safe} {
+		for _, c := range changes {
+			if c.Severity == severity {
+				fmt.Fprintf(w, "%-9s %s\n", c.Severity, c.Message)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s\n", Summary(changes))
+}
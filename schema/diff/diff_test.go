@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/schema/sdl"
+)
+
+const oldSchema = `
+type Query {
+  user(id: ID!, verbose: Boolean = false): User
+  users: [User]
+  legacy: String @deprecated
+}
+
+type User {
+  id: ID!
+  name: String
+  email: String
+}
+
+enum Role { ADMIN USER }
+
+input Filter { name: String }
+
+union Result = User
+
+directive @auth(role: Role) on FIELD_DEFINITION | OBJECT
+`
+
+const newSchema = `
+type Query {
+  user(id: ID, verbose: Boolean = true, tenant: ID!): User
+  users: [User!]
+  posts: [Post]
+}
+
+type User {
+  id: ID!
+  name: Int
+}
+
+type Post {
+  id: ID!
+}
+
+enum Role { ADMIN GUEST }
+
+input Filter { name: String, limit: Int! }
+
+union Result = User | Post
+
+directive @auth(role: Role) on FIELD_DEFINITION
+`
+
+func Test_Compare(t *testing.T) {
+	old, err := sdl.Parse(oldSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := sdl.Parse(newSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Severity{
+		"Query.user(id:) ARGUMENT_TYPE_CHANGED":         Safe,
+		"Query.user(verbose:) ARGUMENT_DEFAULT_CHANGED": Dangerous,
+		"Query.user(tenant:) ARGUMENT_ADDED":            Breaking,
+		"Query.users FIELD_TYPE_CHANGED":                Safe,
+		"Query.posts FIELD_ADDED":                       Safe,
+		"Query.legacy FIELD_REMOVED":                    Dangerous,
+		"User.name FIELD_TYPE_CHANGED":                  Breaking,
+		"User.email FIELD_REMOVED":                      Breaking,
+		"Post TYPE_ADDED":                               Safe,
+		"Role.USER ENUM_VALUE_REMOVED":                  Breaking,
+		"Role.GUEST ENUM_VALUE_ADDED":                   Dangerous,
+		"Filter.limit INPUT_FIELD_ADDED":                Breaking,
+		"Result UNION_MEMBER_ADDED":                     Dangerous,
+		"@auth DIRECTIVE_LOCATION_REMOVED":              Breaking,
+	}
+
+	changes := Compare(old, new)
+
+	got := make(map[string]Severity)
+	for _, c := range changes {
+		got[c.Path+" "+string(c.Kind)] = c.Severity
+	}
+
+	for key, severity := range want {
+		if got[key] != severity {
+			t.Errorf("change %s = %q, want %q", key, got[key], severity)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d changes, want %d: %v", len(got), len(want), changes)
+	}
+
+	if !HasBreaking(changes) {
+		t.Error("HasBreaking() = false, want true")
+	}
+
+	if changes := Compare(old, old); len(changes) != 0 {
+		t.Errorf("Compare() of the same schema = %v, want no changes", changes)
+	}
+}
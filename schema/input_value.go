@@ -10,7 +10,5 @@ type InputValue struct {
 }
 
 func (i *InputValue) Compile() string {
-	argTypeString := buildArgTypeString(i.Type)
-
-	return fmt.Sprintf("$%s: %s", i.Name, argTypeString)
+	return fmt.Sprintf("$%s: %s", i.Name, i.Type)
 }
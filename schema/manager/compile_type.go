@@ -2,21 +2,39 @@ package manager
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/TheLeeeo/gql-test-suite/schema"
+	"golang.org/x/exp/slices"
 )
 
 // CompileFields builds a partial query for the fields of a type and returns the lines of the query
 func (m *Manager) CompileType(t schema.Type) []string {
+	return m.compileType(t, 1, []string{t.Name})
+}
+
+// compileType compiles the type at the given selection depth.
+// The path holds the names of the types being compiled, which are not recursed into again
+func (m *Manager) compileType(t schema.Type, depth int, path []string) []string {
 	if t.Kind == schema.ListTypeKind || t.Kind == schema.NonNullTypeKind {
 		return []string{} //Temporary behavior
 	}
 
 	var fieldNames []string
+	selected := 0
 
 	for _, f := range t.Fields {
+		if m.cfg.MaxFieldsPerType > 0 && selected >= m.cfg.MaxFieldsPerType {
+			break
+		}
+
 		// Field is optional
-		if f.Type.Kind != schema.NonNullTypeKind {
+		if f.Type.Kind != schema.NonNullTypeKind && !m.cfg.IncludeNullable {
+			continue
+		}
+
+		// No values are generated for the arguments of nested fields
+		if hasRequiredArgs(f) {
 			continue
 		}
 
@@ -28,14 +46,31 @@ func (m *Manager) CompileType(t schema.Type) []string {
 		case schema.ScalarTypeKind:
 			fieldNames = append(fieldNames, f.Name)
 		case schema.ObjectTypeKind:
+			if !m.canRecurse(baseType.Name, depth, path) {
+				continue
+			}
+
+			fullBaseType := m.Types[baseType.Name]
+
+			fieldNames = append(fieldNames, fmt.Sprintf("%s {", f.Name))
+			fieldNames = append(fieldNames, m.compileType(fullBaseType, depth+1, append(path, baseType.Name))...)
+			fieldNames = append(fieldNames, "}")
+		case schema.InterfaceTypeKind, schema.UnionTypeKind:
+			if !m.canRecurse(baseType.Name, depth, path) {
+				continue
+			}
+
 			fullBaseType := m.Types[baseType.Name]
 
 			fieldNames = append(fieldNames, fmt.Sprintf("%s {", f.Name))
-			fieldNames = append(fieldNames, m.CompileType(fullBaseType)...)
+			fieldNames = append(fieldNames, m.compileAbstractType(fullBaseType, depth+1, append(path, baseType.Name))...)
 			fieldNames = append(fieldNames, "}")
 		default:
 			fmt.Printf("Unhandled type %s in type %s\n", baseType.Kind, f.Name)
+			continue
 		}
+
+		selected++
 	}
 
 	// At least one selection field is required
@@ -46,30 +81,219 @@ func (m *Manager) CompileType(t schema.Type) []string {
 	return fieldNames
 }
 
+// canRecurse checks if a field of the named type can be selected at the depth
+// without exceeding the max depth or repeating a type already in the path
+func (m *Manager) canRecurse(typeName string, depth int, path []string) bool {
+	maxDepth := m.cfg.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	if depth >= maxDepth {
+		return false
+	}
+
+	return !slices.Contains(path, typeName)
+}
+
+// CompileAbstractType builds a partial query for an interface or union,
+// selecting the typename and the fields of every possible type through inline fragments
+func (m *Manager) CompileAbstractType(t schema.Type) []string {
+	return m.compileAbstractType(t, 1, []string{t.Name})
+}
+
+func (m *Manager) compileAbstractType(t schema.Type, depth int, path []string) []string {
+	lines := []string{"__typename"}
+
+	for _, possibleType := range t.PossibleTypes {
+		if slices.Contains(path, possibleType.Name) {
+			continue
+		}
+
+		fullPossibleType := m.Types[possibleType.Name]
+
+		lines = append(lines, fmt.Sprintf("... on %s {", possibleType.Name))
+		lines = append(lines, m.compileType(fullPossibleType, depth, append(path, possibleType.Name))...)
+		lines = append(lines, "}")
+	}
+
+	return lines
+}
+
 func (m *Manager) CompileField(f schema.Field) string {
+	return m.CompileFieldWithSelection(f, m.compileSelection(f))
+}
+
+// compileSelection returns the lines of the selection of the field
+func (m *Manager) compileSelection(f schema.Field) []string {
 	baseType := f.Type.GetBaseType()
 
-	var queryBody string
-	if baseType.Kind == schema.ScalarTypeKind {
-		queryBody = ""
-	} else if baseType.Kind == schema.ObjectTypeKind {
-		completeBaseType := m.Types[baseType.Name]
-		compiledTypeList := m.CompileType(completeBaseType)
+	switch baseType.Kind {
+	case schema.ScalarTypeKind, schema.EnumTypeKind:
+		// Leaf types have no selection
+		return nil
+	case schema.ObjectTypeKind:
+		return m.CompileType(m.Types[baseType.Name])
+	case schema.InterfaceTypeKind, schema.UnionTypeKind:
+		return m.CompileAbstractType(m.Types[baseType.Name])
+	default:
+		panic(fmt.Sprintf("Unhandled type %s in field %s", baseType.Kind, f.Name))
+	}
+}
 
+// CompileFieldWithSelection compiles the field with its arguments and the given lines as the selection
+func (m *Manager) CompileFieldWithSelection(f schema.Field, compiledTypeList []string) string {
+	var queryBody string
+	if len(compiledTypeList) > 0 {
 		var fields string
 		for _, l := range compiledTypeList {
 			fields += fmt.Sprintf("\n%s", l)
 		}
 
 		queryBody = fmt.Sprintf("{%s\n}", fields)
-	} else {
-		panic(fmt.Sprintf("Unhandled type %s in field %s", baseType.Kind, f.Name))
 	}
 
 	var input string
-	if len(f.Args) > 0 {
-		input = fmt.Sprintf(" (%s: $%s)", f.Args[0].Name, f.Args[0].Name)
+	if args := m.RequestArgs(f); len(args) > 0 {
+		params := make([]string, len(args))
+		for i, arg := range args {
+			params[i] = fmt.Sprintf("%s: $%s", arg.Name, arg.Name)
+		}
+
+		input = fmt.Sprintf(" (%s)", strings.Join(params, ", "))
 	}
 
 	return fmt.Sprintf("%s%s%s", f.Name, input, queryBody)
 }
+
+// LeafPaths returns the path to every leaf field selected when compiling the field.
+// The paths start below the field itself and inline fragments are included as "... on Type" segments
+func (m *Manager) LeafPaths(f schema.Field) [][]string {
+	var paths [][]string
+	var current []string
+
+	for _, line := range m.compileSelection(f) {
+		switch {
+		case line == "}":
+			current = current[:len(current)-1]
+		case strings.HasSuffix(line, " {"):
+			current = append(current, strings.TrimSuffix(line, " {"))
+		case line == "__typename":
+			continue
+		default:
+			paths = append(paths, append(slices.Clone(current), line))
+		}
+	}
+
+	return paths
+}
+
+// pathSelection builds the lines of a selection containing only the leaf at the end of the path
+func pathSelection(path []string) []string {
+	var lines []string
+	for _, segment := range path[:len(path)-1] {
+		lines = append(lines, fmt.Sprintf("%s {", segment))
+	}
+
+	lines = append(lines, path[len(path)-1])
+
+	for range path[:len(path)-1] {
+		lines = append(lines, "}")
+	}
+
+	return lines
+}
+
+// MinimalSelection returns the smallest selection of the field, the typename for composite types and none for leaf types
+func (m *Manager) MinimalSelection(f schema.Field) []string {
+	switch f.Type.GetBaseType().Kind {
+	case schema.ScalarTypeKind, schema.EnumTypeKind:
+		return nil
+	default:
+		return []string{"__typename"}
+	}
+}
+
+// NestedSelection returns a selection of the field with a leaf field nested at most depth levels below it, along with the depth reached.
+// Object fields are followed even if their type repeats, letting recursive schemas nest to any depth.
+// Fields with required arguments and unions are not followed, as no values or fragments are generated for them
+func (m *Manager) NestedSelection(f schema.Field, depth int) ([]string, int) {
+	start := f.Type.GetBaseType()
+	if depth < 1 || (start.Kind != schema.ObjectTypeKind && start.Kind != schema.InterfaceTypeKind) {
+		return nil, 0
+	}
+
+	// reachable[h] holds the types that can nest h more levels of objects below them
+	reachable := []map[string]bool{make(map[string]bool)}
+	for name, t := range m.Types {
+		if t.Kind == schema.ObjectTypeKind || t.Kind == schema.InterfaceTypeKind {
+			reachable[0][name] = true
+		}
+	}
+
+	for h := 1; h < depth; h++ {
+		next := make(map[string]bool)
+		for name := range reachable[0] {
+			if _, ok := m.nestedField(m.Types[name], reachable[h-1]); ok {
+				next[name] = true
+			}
+		}
+
+		if !next[start.Name] {
+			break
+		}
+		reachable = append(reachable, next)
+	}
+
+	var opening []string
+	t := m.Types[start.Name]
+	for h := len(reachable) - 1; h > 0; h-- {
+		field, _ := m.nestedField(t, reachable[h-1])
+		opening = append(opening, fmt.Sprintf("%s {", field.Name))
+		t = m.Types[field.Type.GetBaseType().Name]
+	}
+
+	lines := append(opening, leafField(t))
+	for range opening {
+		lines = append(lines, "}")
+	}
+
+	return lines, len(reachable)
+}
+
+// nestedField returns the first field of the type without required arguments whose type is one of the types
+func (m *Manager) nestedField(t schema.Type, types map[string]bool) (schema.Field, bool) {
+	for _, f := range t.Fields {
+		if hasRequiredArgs(f) {
+			continue
+		}
+
+		if types[f.Type.GetBaseType().Name] {
+			return f, true
+		}
+	}
+
+	return schema.Field{}, false
+}
+
+// leafField returns the name of the first leaf field of the type without required arguments, or the typename if there is none
+func leafField(t schema.Type) string {
+	for _, f := range t.Fields {
+		kind := f.Type.GetBaseType().Kind
+		if (kind == schema.ScalarTypeKind || kind == schema.EnumTypeKind) && !hasRequiredArgs(f) {
+			return f.Name
+		}
+	}
+
+	return "__typename"
+}
+
+func hasRequiredArgs(f schema.Field) bool {
+	for _, arg := range f.Args {
+		if arg.Type.Kind == schema.NonNullTypeKind {
+			return true
+		}
+	}
+
+	return false
+}
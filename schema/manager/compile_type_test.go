@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/TheLeeeo/gql-test-suite/schema"
@@ -126,7 +127,7 @@ func Test_CompileFields(t *testing.T) {
 		},
 	}
 
-	m := New(nil)
+	m := New(nil, Config{})
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -136,3 +137,256 @@ func Test_CompileFields(t *testing.T) {
 		})
 	}
 }
+
+func Test_CompileAbstractType(t *testing.T) {
+	nonNullScalar := &schema.Type{
+		Kind:   schema.NonNullTypeKind,
+		OfType: &schema.Type{Kind: schema.ScalarTypeKind, Name: "String"},
+	}
+
+	m := New(&schema.Schema{
+		Types: []schema.Type{
+			{
+				Kind: schema.ObjectTypeKind,
+				Name: "User",
+				Fields: []schema.Field{
+					{Name: "name", Type: nonNullScalar},
+				},
+			},
+			{
+				Kind: schema.ObjectTypeKind,
+				Name: "Post",
+				Fields: []schema.Field{
+					{Name: "title", Type: nonNullScalar},
+				},
+			},
+		},
+	}, Config{})
+
+	tests := []struct {
+		name string
+		t    schema.Type
+		want []string
+	}{
+		{
+			name: "Union",
+			t: schema.Type{
+				Kind: schema.UnionTypeKind,
+				Name: "SearchResult",
+				PossibleTypes: []schema.Type{
+					{Kind: schema.ObjectTypeKind, Name: "User"},
+					{Kind: schema.ObjectTypeKind, Name: "Post"},
+				},
+			},
+			want: []string{"__typename", "... on User {", "name", "}", "... on Post {", "title", "}"},
+		},
+		{
+			name: "InterfaceWithoutImplementations",
+			t: schema.Type{
+				Kind: schema.InterfaceTypeKind,
+				Name: "Node",
+			},
+			want: []string{"__typename"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.CompileAbstractType(tt.t); !slices.Equal(got, tt.want) {
+				t.Errorf("CompileAbstractType(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CompileTypeConfig(t *testing.T) {
+	nonNull := func(t *schema.Type) *schema.Type {
+		return &schema.Type{Kind: schema.NonNullTypeKind, OfType: t}
+	}
+	str := &schema.Type{Kind: schema.ScalarTypeKind, Name: "String"}
+
+	user := schema.Type{
+		Kind: schema.ObjectTypeKind,
+		Name: "User",
+		Fields: []schema.Field{
+			{Name: "name", Type: nonNull(str)},
+			{Name: "email", Type: str},
+			{Name: "manager", Type: nonNull(&schema.Type{Kind: schema.ObjectTypeKind, Name: "User"})},
+			{Name: "team", Type: nonNull(&schema.Type{Kind: schema.ObjectTypeKind, Name: "Team"})},
+			{Name: "avatar", Type: nonNull(str), Args: []schema.InputValue{{Name: "size", Type: nonNull(str)}}},
+			{Name: "friend", Type: &schema.Type{Kind: schema.ObjectTypeKind, Name: "Team"}, Args: []schema.InputValue{{Name: "id", Type: nonNull(str)}}},
+		},
+	}
+	team := schema.Type{
+		Kind: schema.ObjectTypeKind,
+		Name: "Team",
+		Fields: []schema.Field{
+			{Name: "title", Type: nonNull(str)},
+			{Name: "lead", Type: nonNull(&schema.Type{Kind: schema.ObjectTypeKind, Name: "User"})},
+		},
+	}
+	s := &schema.Schema{Types: []schema.Type{user, team}}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "Cycles",
+			cfg:  Config{},
+			want: []string{"name", "team {", "title", "}"},
+		},
+		{
+			name: "MaxDepth",
+			cfg:  Config{MaxDepth: 1},
+			want: []string{"name"},
+		},
+		{
+			name: "IncludeNullable",
+			cfg:  Config{IncludeNullable: true, MaxDepth: 1},
+			want: []string{"name", "email"},
+		},
+		{
+			name: "IncludeNullable nested",
+			cfg:  Config{IncludeNullable: true, MaxDepth: 2},
+			want: []string{"name", "email", "team {", "title", "}"},
+		},
+		{
+			name: "MaxFieldsPerType",
+			cfg:  Config{MaxFieldsPerType: 1},
+			want: []string{"name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(s, tt.cfg)
+			if got := m.CompileType(user); !slices.Equal(got, tt.want) {
+				t.Errorf("CompileType(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_CompileTypeDefaultDepth(t *testing.T) {
+	// Every type references itself and every other type, which without a depth limit selects every ordering of the types
+	names := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	var types []schema.Type
+	for _, name := range names {
+		fields := []schema.Field{
+			{Name: "id", Type: &schema.Type{Kind: schema.NonNullTypeKind, OfType: &schema.Type{Kind: schema.ScalarTypeKind, Name: "ID"}}},
+		}
+		for _, other := range names {
+			fields = append(fields, schema.Field{
+				Name: strings.ToLower(other),
+				Type: &schema.Type{Kind: schema.NonNullTypeKind, OfType: &schema.Type{Kind: schema.ObjectTypeKind, Name: other}},
+			})
+		}
+
+		types = append(types, schema.Type{Kind: schema.ObjectTypeKind, Name: name, Fields: fields})
+	}
+
+	m := New(&schema.Schema{Types: types}, Config{})
+	lines := m.CompileType(types[0])
+
+	nesting, deepest := 0, 0
+	for _, l := range lines {
+		switch {
+		case strings.HasSuffix(l, " {"):
+			nesting++
+			deepest = max(deepest, nesting)
+		case l == "}":
+			nesting--
+		}
+	}
+
+	if deepest != DefaultMaxDepth-1 {
+		t.Errorf("CompileType() nesting, got %d, want %d", deepest, DefaultMaxDepth-1)
+	}
+
+	// Bounded by the 7 * 6 * 5 * 4 paths through distinct types down to the default depth
+	if len(lines) > 10000 {
+		t.Errorf("CompileType(), got %d lines, want at most 10000", len(lines))
+	}
+}
+
+func Test_NestedSelection(t *testing.T) {
+	object := func(name string) *schema.Type {
+		return &schema.Type{Kind: schema.ObjectTypeKind, Name: name}
+	}
+	str := &schema.Type{Kind: schema.ScalarTypeKind, Name: "String"}
+
+	user := schema.Type{
+		Kind: schema.ObjectTypeKind,
+		Name: "User",
+		Fields: []schema.Field{
+			{Name: "name", Type: str},
+			{Name: "friend", Type: object("User"), Args: []schema.InputValue{
+				{Name: "id", Type: &schema.Type{Kind: schema.NonNullTypeKind, OfType: str}},
+			}},
+			{Name: "team", Type: object("Team")},
+		},
+	}
+	team := schema.Type{
+		Kind: schema.ObjectTypeKind,
+		Name: "Team",
+		Fields: []schema.Field{
+			{Name: "lead", Type: object("User")},
+			{Name: "title", Type: str},
+		},
+	}
+	office := schema.Type{
+		Kind: schema.ObjectTypeKind,
+		Name: "Office",
+		Fields: []schema.Field{
+			{Name: "city", Type: str},
+		},
+	}
+	m := New(&schema.Schema{Types: []schema.Type{user, team, office}}, Config{})
+
+	tests := []struct {
+		name      string
+		field     schema.Field
+		depth     int
+		want      []string
+		wantDepth int
+	}{
+		{
+			name:      "Leaf",
+			field:     schema.Field{Name: "version", Type: str},
+			depth:     3,
+			wantDepth: 0,
+		},
+		{
+			name:      "Single",
+			field:     schema.Field{Name: "me", Type: object("User")},
+			depth:     1,
+			want:      []string{"name"},
+			wantDepth: 1,
+		},
+		{
+			name:      "Recursive",
+			field:     schema.Field{Name: "me", Type: object("User")},
+			depth:     4,
+			want:      []string{"team {", "lead {", "team {", "title", "}", "}", "}"},
+			wantDepth: 4,
+		},
+		{
+			name:      "Bounded",
+			field:     schema.Field{Name: "office", Type: object("Office")},
+			depth:     4,
+			want:      []string{"city"},
+			wantDepth: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, depth := m.NestedSelection(tt.field, tt.depth)
+			if !slices.Equal(got, tt.want) || depth != tt.wantDepth {
+				t.Errorf("NestedSelection(), got %v at depth %d, want %v at depth %d", got, depth, tt.want, tt.wantDepth)
+			}
+		})
+	}
+}
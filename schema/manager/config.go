@@ -0,0 +1,19 @@
+package manager
+
+// DefaultMaxDepth is the maximum number of nested selections when none is configured.
+// Cycles are only broken within a single path, so an unbounded selection grows exponentially with the types of the schema
+const DefaultMaxDepth = 5
+
+type Config struct {
+	// Also declare and send the optional arguments of operations
+	IncludeOptionalArgs bool
+
+	// The maximum number of nested selections, 0 means DefaultMaxDepth
+	MaxDepth int
+
+	// Also select nullable fields
+	IncludeNullable bool
+
+	// The maximum number of fields to select per type, 0 means no limit
+	MaxFieldsPerType int
+}
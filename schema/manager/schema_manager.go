@@ -2,62 +2,120 @@ package manager
 
 import (
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/TheLeeeo/gql-test-suite/client"
 	"github.com/TheLeeeo/gql-test-suite/schema"
 )
 
 type Manager struct {
+	cfg Config
+
 	Types     map[string]schema.Type
 	Queries   map[string]schema.Field
 	Mutations map[string]schema.Field
+	// The fields of the subscription type
+	Subscriptions map[string]schema.Field
 }
 
-func New(s *schema.Schema) *Manager {
+func New(s *schema.Schema, cfg Config) *Manager {
 	m := &Manager{
+		cfg:       cfg,
 		Types:     make(map[string]schema.Type),
 		Queries:   make(map[string]schema.Field),
 		Mutations: make(map[string]schema.Field),
+
+		Subscriptions: make(map[string]schema.Field),
+	}
+
+	if s == nil {
+		return m
 	}
 
 	for _, t := range s.Types {
 		m.Types[t.Name] = t
 	}
 
-	queries, ok := m.Types["Query"]
+	queries, ok := m.Types[RootTypeName(s.QueryType, "Query")]
 	if ok && len(queries.Fields) > 0 {
 		for _, f := range queries.Fields {
 			m.Queries[f.Name] = f
 		}
 	}
 
-	mutations, ok := m.Types["Mutation"]
+	mutations, ok := m.Types[RootTypeName(s.MutationType, "Mutation")]
 	if ok && len(mutations.Fields) > 0 {
 		for _, f := range mutations.Fields {
 			m.Mutations[f.Name] = f
 		}
 	}
 
-	_, ok = m.Types["Subscription"]
-	if ok {
-		log.Println("Schema contains subscriptions, these are not supported :(")
+	subscriptions, ok := m.Types[RootTypeName(s.SubscriptionType, "Subscription")]
+	if ok && len(subscriptions.Fields) > 0 {
+		for _, f := range subscriptions.Fields {
+			m.Subscriptions[f.Name] = f
+		}
 	}
 
 	return m
 }
 
+// RootTypeName returns the name of the root operation type, which the schema may rename
+func RootTypeName(root *schema.Type, defaultName string) string {
+	if root == nil || root.Name == "" {
+		return defaultName
+	}
+
+	return root.Name
+}
+
 func (c *Manager) Build(requestField schema.Field, t client.RequestType) string {
-	if t != client.QueryRequest && t != client.MutationRequest {
+	return c.build(requestField, t, c.CompileField(requestField))
+}
+
+// BuildPath builds a request for the field selecting only the leaf field at the path, as returned by LeafPaths
+func (c *Manager) BuildPath(requestField schema.Field, path []string, t client.RequestType) string {
+	return c.build(requestField, t, c.CompileFieldWithSelection(requestField, pathSelection(path)))
+}
+
+// BuildFields builds a request of the compiled fields, declaring the arguments of the request field which they share.
+// The fields are compiled from the request field, eg. with aliases or different selections
+func (c *Manager) BuildFields(requestField schema.Field, compiledFields []string, t client.RequestType) string {
+	return c.build(requestField, t, strings.Join(compiledFields, "\n"))
+}
+
+// build wraps the compiled field in an operation of the request type declaring the arguments of the field
+func (c *Manager) build(requestField schema.Field, t client.RequestType, compiledField string) string {
+	if t != client.QueryRequest && t != client.MutationRequest && t != client.SubscriptionRequest {
 		panic(fmt.Sprintf("invalid request type: %s", t))
 	}
 
 	var input string
-	if len(requestField.Args) > 0 {
-		input = fmt.Sprintf(" (%s)", requestField.Args[0].Compile())
+	if args := c.RequestArgs(requestField); len(args) > 0 {
+		declarations := make([]string, len(args))
+		for i, arg := range args {
+			declarations[i] = arg.Compile()
+		}
+
+		input = fmt.Sprintf(" (%s)", strings.Join(declarations, ", "))
 	}
 
-	requestString := fmt.Sprintf("%s%s{\n%s\n}", t, input, c.CompileField(requestField))
+	requestString := fmt.Sprintf("%s%s{\n%s\n}", t, input, compiledField)
 
 	return requestString
 }
+
+// RequestArgs returns the arguments of the field that are declared and sent with a request.
+// These are the required arguments, and the optional ones if configured to include them
+func (c *Manager) RequestArgs(f schema.Field) []schema.InputValue {
+	var args []schema.InputValue
+	for _, arg := range f.Args {
+		if arg.Type.Kind != schema.NonNullTypeKind && !c.cfg.IncludeOptionalArgs {
+			continue
+		}
+
+		args = append(args, arg)
+	}
+
+	return args
+}
@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/client"
+	"github.com/TheLeeeo/gql-test-suite/schema"
+)
+
+func Test_Build(t *testing.T) {
+	field := schema.Field{
+		Name: "users",
+		Args: []schema.InputValue{
+			{
+				Name: "first",
+				Type: &schema.Type{
+					Kind:   schema.NonNullTypeKind,
+					OfType: &schema.Type{Kind: schema.ScalarTypeKind, Name: "Int"},
+				},
+			},
+			{
+				Name: "filter",
+				Type: &schema.Type{
+					Kind:   schema.NonNullTypeKind,
+					OfType: &schema.Type{Kind: schema.ScalarTypeKind, Name: "String"},
+				},
+			},
+			{
+				Name: "after",
+				Type: &schema.Type{Kind: schema.ScalarTypeKind, Name: "String"},
+			},
+		},
+		Type: &schema.Type{
+			Kind: schema.ScalarTypeKind,
+			Name: "Int",
+		},
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "RequiredArgs",
+			cfg:  Config{},
+			want: "query ($first: Int!, $filter: String!){\nusers (first: $first, filter: $filter)\n}",
+		},
+		{
+			name: "OptionalArgs",
+			cfg:  Config{IncludeOptionalArgs: true},
+			want: "query ($first: Int!, $filter: String!, $after: String){\nusers (first: $first, filter: $filter, after: $after)\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(nil, tt.cfg)
+			if got := m.Build(field, client.QueryRequest); got != tt.want {
+				t.Errorf("Build(), got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package scalars
+
+import "fmt"
+
+var builtinGenerators = map[string]Generator{
+	"Boolean": constant(true),
+	"String":  constant("0"),
+	"Int":     constant(0),
+	"Float":   constant(0.0),
+	"ID":      constant("0"),
+	"Time":    dateTimeGenerator,
+
+	"DateTime":     dateTimeGenerator,
+	"Date":         dateGenerator,
+	"UUID":         uuidGenerator,
+	"JSON":         jsonGenerator,
+	"JSONObject":   jsonGenerator,
+	"Email":        emailGenerator,
+	"EmailAddress": emailGenerator,
+	"BigInt":       bigIntGenerator,
+	"Long":         bigIntGenerator,
+	"URL":          urlGenerator,
+	"URI":          urlGenerator,
+	"Upload":       uploadGenerator,
+}
+
+func constant(v any) Generator {
+	return func() (any, error) {
+		return v, nil
+	}
+}
+
+var (
+	dateTimeGenerator = constant("2019-01-01T00:00:00Z")
+	dateGenerator     = constant("2019-01-01")
+	timeGenerator     = constant("00:00:00Z")
+	uuidGenerator     = constant("00000000-0000-0000-0000-000000000000")
+	emailGenerator    = constant("test@example.com")
+	urlGenerator      = constant("https://example.com")
+	// Big integers are commonly serialized as strings to not lose precision
+	bigIntGenerator  = constant("0")
+	decimalGenerator = constant("0.0")
+)
+
+func jsonGenerator() (any, error) {
+	return map[string]any{}, nil
+}
+
+// Files are sent as multipart requests, which can not be done with json variables
+func uploadGenerator() (any, error) {
+	return nil, fmt.Errorf("%w: files can not be sent as json variables", ErrUnsupportedScalar)
+}
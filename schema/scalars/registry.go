@@ -0,0 +1,178 @@
+// Package scalars generates values for built-in and custom graphql scalars
+package scalars
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownScalar = errors.New("unknown scalar")
+	// The scalar is known, but no value of it can be generated
+	ErrUnsupportedScalar = errors.New("unsupported scalar")
+)
+
+// A Generator produces a value that is accepted as a scalar
+type Generator func() (any, error)
+
+type Registry struct {
+	generators map[string]Generator
+}
+
+// New creates a registry with generators for the built-in scalars and commonly used custom scalars
+func New() *Registry {
+	r := &Registry{
+		generators: make(map[string]Generator),
+	}
+
+	for name, g := range builtinGenerators {
+		r.Register(name, g)
+	}
+
+	return r
+}
+
+// Register sets the generator used for the scalar with the given name
+func (r *Registry) Register(name string, g Generator) {
+	r.generators[name] = g
+}
+
+// RegisterValue sets a literal value to use for the scalar with the given name
+func (r *Registry) RegisterValue(name string, value any) {
+	r.Register(name, constant(value))
+}
+
+// Generate produces a value for a scalar. The generator is looked up by name first,
+// then inferred from the specifiedByURL and lastly from the name of the scalar.
+// Returns an ErrUnknownScalar if no generator could be found
+func (r *Registry) Generate(name string, specifiedByURL string) (any, error) {
+	if g, ok := r.generators[name]; ok {
+		return g()
+	}
+
+	if g, ok := inferFromURL(specifiedByURL); ok {
+		return g()
+	}
+
+	if g, ok := inferFromName(name); ok {
+		return g()
+	}
+
+	return nil, fmt.Errorf("%w %s", ErrUnknownScalar, name)
+}
+
+var defaultRegistry = New()
+
+// Generate produces a value for a scalar using the default registry
+func Generate(name string, specifiedByURL string) (any, error) {
+	return defaultRegistry.Generate(name, specifiedByURL)
+}
+
+// A heuristic maps a substring of a url, or one or more whole words of a name, to a generator
+type heuristic struct {
+	substring string
+	generator Generator
+}
+
+// Checked in order, so more specific substrings come first
+var urlHeuristics = []heuristic{
+	{"rfc3339", dateTimeGenerator},
+	{"rfc4122", uuidGenerator},
+	{"uuid", uuidGenerator},
+	{"rfc5322", emailGenerator},
+	{"rfc3986", urlGenerator},
+	{"ecma-404", jsonGenerator},
+	{"rfc8259", jsonGenerator},
+	{"iso8601", dateTimeGenerator},
+}
+
+// Checked in order, so more specific substrings come first
+var nameHeuristics = []heuristic{
+	{"datetime", dateTimeGenerator},
+	{"timestamp", dateTimeGenerator},
+	{"date", dateGenerator},
+	{"time", timeGenerator},
+	{"uuid", uuidGenerator},
+	{"guid", uuidGenerator},
+	{"email", emailGenerator},
+	{"url", urlGenerator},
+	{"uri", urlGenerator},
+	{"json", jsonGenerator},
+	{"bigint", bigIntGenerator},
+	{"long", bigIntGenerator},
+	{"decimal", decimalGenerator},
+	{"upload", uploadGenerator},
+}
+
+func inferFromURL(url string) (Generator, bool) {
+	if url == "" {
+		return nil, false
+	}
+
+	return match(strings.ToLower(url), urlHeuristics)
+}
+
+// inferFromName matches the heuristics to whole words of the name,
+// so that eg. ISODateTime is a date time but Runtime and Longitude are not a time and a long
+func inferFromName(name string) (Generator, bool) {
+	lower := strings.ToLower(name)
+	boundaries := wordBoundaries(name)
+
+	for _, h := range nameHeuristics {
+		for i := 0; i+len(h.substring) <= len(lower); i++ {
+			if boundaries[i] && boundaries[i+len(h.substring)] && strings.HasPrefix(lower[i:], h.substring) {
+				return h.generator, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// wordBoundaries returns the indexes of the name at which a word starts or ends, splitting camel case,
+// acronyms, digits and underscores, eg. ISODate_v2 is split into ISO, Date, v and 2
+func wordBoundaries(name string) map[int]bool {
+	boundaries := map[int]bool{0: true, len(name): true}
+
+	kind := func(c byte) int {
+		switch {
+		case c >= 'a' && c <= 'z':
+			return 1
+		case c >= 'A' && c <= 'Z':
+			return 2
+		case c >= '0' && c <= '9':
+			return 3
+		default:
+			return 0
+		}
+	}
+
+	for i := 1; i < len(name); i++ {
+		prev, cur := kind(name[i-1]), kind(name[i])
+
+		switch {
+		case prev == 0 || cur == 0:
+			boundaries[i] = true
+		case prev == 3 || cur == 3:
+			boundaries[i] = prev != cur
+		case prev == 1 && cur == 2:
+			boundaries[i] = true
+		// The last capital of an acronym starts the next word, eg. the D of ISODate
+		case prev == 2 && cur == 2 && i+1 < len(name) && kind(name[i+1]) == 1:
+			boundaries[i] = true
+		}
+	}
+
+	return boundaries
+}
+
+func match(s string, heuristics []heuristic) (Generator, bool) {
+	for _, h := range heuristics {
+		if strings.Contains(s, h.substring) {
+			return h.generator, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,90 @@
+package scalars
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_Generate(t *testing.T) {
+	r := New()
+	r.RegisterValue("Money", "1.00")
+
+	tests := []struct {
+		name           string
+		scalar         string
+		specifiedByURL string
+		want           any
+		wantErr        error
+	}{
+		{
+			name:   "Builtin",
+			scalar: "Int",
+			want:   0,
+		},
+		{
+			name:   "Registered",
+			scalar: "Money",
+			want:   "1.00",
+		},
+		{
+			name:           "SpecifiedByURL",
+			scalar:         "Identifier",
+			specifiedByURL: "https://tools.ietf.org/html/rfc4122",
+			want:           "00000000-0000-0000-0000-000000000000",
+		},
+		{
+			name:   "NameHeuristic",
+			scalar: "ISODateTime",
+			want:   "2019-01-01T00:00:00Z",
+		},
+		{
+			name:   "NameHeuristicWords",
+			scalar: "Created_at_timestamp",
+			want:   "2019-01-01T00:00:00Z",
+		},
+		{
+			name:   "NameHeuristicAcronym",
+			scalar: "JSONObjectV2",
+			want:   map[string]any{},
+		},
+		{
+			name:    "NameHeuristicInsideWord",
+			scalar:  "Runtime",
+			wantErr: ErrUnknownScalar,
+		},
+		{
+			name:    "NameHeuristicPrefixOfWord",
+			scalar:  "Longitude",
+			wantErr: ErrUnknownScalar,
+		},
+		{
+			name:    "Upload",
+			scalar:  "Upload",
+			wantErr: ErrUnsupportedScalar,
+		},
+		{
+			name:    "UploadHeuristic",
+			scalar:  "FileUpload",
+			wantErr: ErrUnsupportedScalar,
+		},
+		{
+			name:    "Unknown",
+			scalar:  "Color",
+			wantErr: ErrUnknownScalar,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Generate(tt.scalar, tt.specifiedByURL)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Generate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Generate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
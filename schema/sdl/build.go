@@ -0,0 +1,263 @@
+package sdl
+
+import (
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"golang.org/x/exp/slices"
+)
+
+// The scalars every schema has, as specified by https://spec.graphql.org/October2021/#sec-Scalars.Built-in-Scalars
+var builtinScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+var directiveLocations = []schema.DirectiveLocation{
+	schema.QueryDirectiveLocation,
+	schema.MutationDirectiveLocation,
+	schema.SubscriptionDirectiveLocation,
+	schema.FieldDirectiveLocation,
+	schema.FragmentDefinitionDirectiveLocation,
+	schema.FragmentSpreadDirectiveLocation,
+	schema.InlineFragmentDirectiveLocation,
+	schema.VariableDefinitionDirectiveLocation,
+	schema.SchemaDirectiveLocation,
+	schema.ScalarDirectiveLocation,
+	schema.ObjectDirectiveLocation,
+	schema.FieldDefinitionDirectiveLocation,
+	schema.ArgumentDefinitionDirectiveLocation,
+	schema.InterfaceDirectiveLocation,
+	schema.UnionDirectiveLocation,
+	schema.EnumDirectiveLocation,
+	schema.EnumValueDirectiveLocation,
+	schema.InputObjectDirectiveLocation,
+	schema.InputFieldDefinitionDirectiveLocation,
+}
+
+// builtinDirectives returns the directives every schema has,
+// as specified by https://spec.graphql.org/October2021/#sec-Type-System.Directives.Built-in-Directives
+func builtinDirectives() []schema.Directive {
+	scalar := func(name string) *schema.Type {
+		return &schema.Type{Kind: schema.ScalarTypeKind, Name: name}
+	}
+	nonNull := func(t *schema.Type) *schema.Type {
+		return &schema.Type{Kind: schema.NonNullTypeKind, OfType: t}
+	}
+
+	return []schema.Directive{
+		{
+			Name:      "include",
+			Args:      []schema.InputValue{{Name: "if", Type: nonNull(scalar("Boolean"))}},
+			Locations: []schema.DirectiveLocation{schema.FieldDirectiveLocation, schema.FragmentSpreadDirectiveLocation, schema.InlineFragmentDirectiveLocation},
+		},
+		{
+			Name:      "skip",
+			Args:      []schema.InputValue{{Name: "if", Type: nonNull(scalar("Boolean"))}},
+			Locations: []schema.DirectiveLocation{schema.FieldDirectiveLocation, schema.FragmentSpreadDirectiveLocation, schema.InlineFragmentDirectiveLocation},
+		},
+		{
+			Name:      "deprecated",
+			Args:      []schema.InputValue{{Name: "reason", Type: scalar("String"), DefaultValue: `"No longer supported"`}},
+			Locations: []schema.DirectiveLocation{schema.FieldDefinitionDirectiveLocation, schema.ArgumentDefinitionDirectiveLocation, schema.InputFieldDefinitionDirectiveLocation, schema.EnumValueDirectiveLocation},
+		},
+		{
+			Name:      "specifiedBy",
+			Args:      []schema.InputValue{{Name: "url", Type: nonNull(scalar("String"))}},
+			Locations: []schema.DirectiveLocation{schema.ScalarDirectiveLocation},
+		},
+	}
+}
+
+// build applies the extensions, resolves the referenced types and assembles the schema
+func (p *parser) build() (*schema.Schema, error) {
+	for _, name := range builtinScalars {
+		if _, ok := p.types[name]; !ok {
+			p.types[name] = &schema.Type{Kind: schema.ScalarTypeKind, Name: name}
+			p.order = append(p.order, name)
+		}
+	}
+
+	for _, ext := range p.extensions {
+		if err := p.extend(ext); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ref := range p.refs {
+		if err := p.resolve(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	// Interfaces and union members are copied out of their references, so their kinds are set separately
+	for _, name := range p.order {
+		t := p.types[name]
+		for i := range t.Interfaces {
+			t.Interfaces[i].Kind = p.types[t.Interfaces[i].Name].Kind
+		}
+		if t.Kind == schema.UnionTypeKind {
+			for i := range t.PossibleTypes {
+				t.PossibleTypes[i].Kind = p.types[t.PossibleTypes[i].Name].Kind
+			}
+		}
+	}
+
+	// Introspection lists the objects implementing an interface as its possible types
+	for _, name := range p.order {
+		t := p.types[name]
+		if t.Kind != schema.ObjectTypeKind {
+			continue
+		}
+
+		for _, i := range t.Interfaces {
+			it := p.types[i.Name]
+			it.PossibleTypes = append(it.PossibleTypes, schema.Type{Kind: schema.ObjectTypeKind, Name: t.Name})
+		}
+	}
+
+	s := &schema.Schema{
+		Types:      make([]schema.Type, 0, len(p.order)),
+		Directives: []schema.Directive{},
+	}
+	for _, name := range p.order {
+		s.Types = append(s.Types, *p.types[name])
+	}
+
+	for _, d := range builtinDirectives() {
+		if _, ok := p.directiveDefs[d.Name]; !ok {
+			s.Directives = append(s.Directives, d)
+		}
+	}
+	for _, name := range p.directiveOrder {
+		s.Directives = append(s.Directives, *p.directiveDefs[name])
+	}
+
+	if err := p.setRoots(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// extend adds the fields, interfaces, members and values of the extension to the type it extends
+func (p *parser) extend(ext extension) error {
+	t, ok := p.types[ext.t.Name]
+	if !ok {
+		return errorf(ext.pos, "cannot extend unknown type %s", ext.t.Name)
+	}
+
+	if t.Kind != ext.t.Kind {
+		return errorf(ext.pos, "cannot extend %s %s as %s", t.Kind, t.Name, ext.t.Kind)
+	}
+
+	for _, f := range ext.t.Fields {
+		if slices.ContainsFunc(t.Fields, func(other schema.Field) bool { return other.Name == f.Name }) {
+			return errorf(ext.pos, "field %s.%s is defined more than once", t.Name, f.Name)
+		}
+		t.Fields = append(t.Fields, f)
+	}
+
+	for _, i := range ext.t.Interfaces {
+		if slices.ContainsFunc(t.Interfaces, func(other schema.Type) bool { return other.Name == i.Name }) {
+			return errorf(ext.pos, "type %s implements %s more than once", t.Name, i.Name)
+		}
+		t.Interfaces = append(t.Interfaces, i)
+	}
+
+	for _, m := range ext.t.PossibleTypes {
+		if slices.ContainsFunc(t.PossibleTypes, func(other schema.Type) bool { return other.Name == m.Name }) {
+			return errorf(ext.pos, "union %s includes %s more than once", t.Name, m.Name)
+		}
+		t.PossibleTypes = append(t.PossibleTypes, m)
+	}
+
+	for _, v := range ext.t.EnumValues {
+		if slices.ContainsFunc(t.EnumValues, func(other schema.EnumValue) bool { return other.Name == v.Name }) {
+			return errorf(ext.pos, "enum value %s.%s is defined more than once", t.Name, v.Name)
+		}
+		t.EnumValues = append(t.EnumValues, v)
+	}
+
+	for _, f := range ext.t.InputFields {
+		if slices.ContainsFunc(t.InputFields, func(other schema.InputValue) bool { return other.Name == f.Name }) {
+			return errorf(ext.pos, "input field %s.%s is defined more than once", t.Name, f.Name)
+		}
+		t.InputFields = append(t.InputFields, f)
+	}
+
+	if ext.t.SpecifiedByURL != "" {
+		t.SpecifiedByURL = ext.t.SpecifiedByURL
+	}
+
+	return nil
+}
+
+// resolve sets the kind of the referenced type, checking that the kind is allowed where it is referenced
+func (p *parser) resolve(ref typeRef) error {
+	t, ok := p.types[ref.t.Name]
+	if !ok {
+		return errorf(ref.pos, "unknown type %s", ref.t.Name)
+	}
+
+	ref.t.Kind = t.Kind
+
+	switch ref.use {
+	case outputRef:
+		if t.Kind == schema.InputObjectTypeKind {
+			return errorf(ref.pos, "input type %s cannot be the type of a field", t.Name)
+		}
+	case inputRef:
+		if t.Kind != schema.ScalarTypeKind && t.Kind != schema.EnumTypeKind && t.Kind != schema.InputObjectTypeKind {
+			return errorf(ref.pos, "%s type %s cannot be the type of an argument or input field", t.Kind, t.Name)
+		}
+	case interfaceRef:
+		if t.Kind != schema.InterfaceTypeKind {
+			return errorf(ref.pos, "%s type %s cannot be implemented, it is not an interface", t.Kind, t.Name)
+		}
+	case memberRef:
+		if t.Kind != schema.ObjectTypeKind {
+			return errorf(ref.pos, "%s type %s cannot be a member of a union, it is not an object", t.Kind, t.Name)
+		}
+	case rootRef:
+		if t.Kind != schema.ObjectTypeKind {
+			return errorf(ref.pos, "%s type %s cannot be a root operation type, it is not an object", t.Kind, t.Name)
+		}
+	}
+
+	return nil
+}
+
+// setRoots sets the root operation types from the schema definition,
+// or from the types named Query, Mutation and Subscription if there is none
+func (p *parser) setRoots(s *schema.Schema) error {
+	roots := map[string]string{}
+
+	// Schema extensions without a schema definition extend the default root operation types
+	if p.schemaDef == nil || p.schemaDef.pos == (Position{}) {
+		for op, name := range map[string]string{"query": "Query", "mutation": "Mutation", "subscription": "Subscription"} {
+			if t, ok := p.types[name]; ok && t.Kind == schema.ObjectTypeKind {
+				roots[op] = name
+			}
+		}
+	}
+
+	if p.schemaDef != nil {
+		s.Description = p.schemaDef.description
+
+		for op, name := range p.schemaDef.roots {
+			roots[op] = name
+		}
+
+		if _, ok := roots["query"]; !ok && p.schemaDef.pos != (Position{}) {
+			return errorf(p.schemaDef.pos, "schema has no query root operation type")
+		}
+	}
+
+	if name, ok := roots["query"]; ok {
+		s.QueryType = &schema.Type{Name: name}
+	}
+	if name, ok := roots["mutation"]; ok {
+		s.MutationType = &schema.Type{Name: name}
+	}
+	if name, ok := roots["subscription"]; ok {
+		s.SubscriptionType = &schema.Type{Name: name}
+	}
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package sdl
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenPunct
+	tokenString
+	tokenBlockString
+	tokenInt
+	tokenFloat
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of input"
+	case tokenName:
+		return "name"
+	case tokenPunct:
+		return "punctuator"
+	case tokenString, tokenBlockString:
+		return "string"
+	case tokenInt:
+		return "int"
+	case tokenFloat:
+		return "float"
+	}
+
+	return "unknown token"
+}
+
+// Position is a location in a source, both line and column start at 1
+type Position struct {
+	// The name of the source, eg. the file name
+	Source string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	if p.Source == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.Source, p.Line, p.Column)
+}
+
+type token struct {
+	kind tokenKind
+	// The value of the token, strings are unescaped
+	value string
+	pos   Position
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return t.kind.String()
+	}
+
+	return fmt.Sprintf("%s %q", t.kind, t.value)
+}
+
+// Error is a syntax or schema error at a position in the source
+type Error struct {
+	Pos     Position
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+func errorf(pos Position, format string, args ...any) *Error {
+	return &Error{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// lexer splits the source into tokens, as specified by https://spec.graphql.org/October2021/#sec-Language.Source-Text
+type lexer struct {
+	name string
+	src  string
+	i    int
+	line int
+	// The index the current line starts at
+	lineStart int
+}
+
+func newLexer(src Source) *lexer {
+	return &lexer{name: src.Name, src: src.Body, line: 1}
+}
+
+func (l *lexer) pos() Position {
+	return Position{Source: l.name, Line: l.line, Column: utf8.RuneCountInString(l.src[l.lineStart:l.i]) + 1}
+}
+
+func (l *lexer) newline(i int) {
+	l.line++
+	l.lineStart = i
+}
+
+// skipIgnored skips whitespace, line terminators, commas and comments
+func (l *lexer) skipIgnored() {
+	for l.i < len(l.src) {
+		switch c := l.src[l.i]; c {
+		case ' ', '\t', ',':
+			l.i++
+		case '\n':
+			l.i++
+			l.newline(l.i)
+		case '\r':
+			l.i++
+			if l.i < len(l.src) && l.src[l.i] == '\n' {
+				l.i++
+			}
+			l.newline(l.i)
+		case '#':
+			for l.i < len(l.src) && l.src[l.i] != '\n' && l.src[l.i] != '\r' {
+				l.i++
+			}
+		default:
+			// The unicode byte order mark
+			if strings.HasPrefix(l.src[l.i:], "\uFEFF") {
+				l.i += len("\uFEFF")
+				continue
+			}
+
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	pos := l.pos()
+	if l.i >= len(l.src) {
+		return token{kind: tokenEOF, pos: pos}, nil
+	}
+
+	c := l.src[l.i]
+	switch {
+	case strings.HasPrefix(l.src[l.i:], "..."):
+		l.i += 3
+		return token{kind: tokenPunct, value: "...", pos: pos}, nil
+	case strings.IndexByte("!$&():=@[]{}|", c) >= 0:
+		l.i++
+		return token{kind: tokenPunct, value: string(c), pos: pos}, nil
+	case c == '_' || isLetter(c):
+		start := l.i
+		for l.i < len(l.src) && (l.src[l.i] == '_' || isLetter(l.src[l.i]) || isDigit(l.src[l.i])) {
+			l.i++
+		}
+		return token{kind: tokenName, value: l.src[start:l.i], pos: pos}, nil
+	case c == '-' || isDigit(c):
+		return l.number(pos)
+	case strings.HasPrefix(l.src[l.i:], `"""`):
+		return l.blockString(pos)
+	case c == '"':
+		return l.string(pos)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.i:])
+	return token{}, errorf(pos, "unexpected character %q", r)
+}
+
+func (l *lexer) number(pos Position) (token, error) {
+	start := l.i
+	kind := tokenInt
+
+	if l.src[l.i] == '-' {
+		l.i++
+	}
+	if !l.digits() {
+		return token{}, errorf(pos, "invalid number, expected digit")
+	}
+
+	if l.i < len(l.src) && l.src[l.i] == '.' {
+		kind = tokenFloat
+		l.i++
+		if !l.digits() {
+			return token{}, errorf(l.pos(), "invalid number, expected digit")
+		}
+	}
+
+	if l.i < len(l.src) && (l.src[l.i] == 'e' || l.src[l.i] == 'E') {
+		kind = tokenFloat
+		l.i++
+		if l.i < len(l.src) && (l.src[l.i] == '+' || l.src[l.i] == '-') {
+			l.i++
+		}
+		if !l.digits() {
+			return token{}, errorf(l.pos(), "invalid number, expected digit")
+		}
+	}
+
+	if l.i < len(l.src) && (l.src[l.i] == '_' || l.src[l.i] == '.' || isLetter(l.src[l.i])) {
+		return token{}, errorf(l.pos(), "invalid number, unexpected %q", l.src[l.i])
+	}
+
+	return token{kind: kind, value: l.src[start:l.i], pos: pos}, nil
+}
+
+// digits consumes a sequence of digits, reporting whether there was at least one
+func (l *lexer) digits() bool {
+	start := l.i
+	for l.i < len(l.src) && isDigit(l.src[l.i]) {
+		l.i++
+	}
+
+	return l.i > start
+}
+
+func (l *lexer) string(pos Position) (token, error) {
+	l.i++
+
+	var b strings.Builder
+	for l.i < len(l.src) {
+		c := l.src[l.i]
+		switch c {
+		case '"':
+			l.i++
+			return token{kind: tokenString, value: b.String(), pos: pos}, nil
+		case '\n', '\r':
+			return token{}, errorf(l.pos(), "unterminated string")
+		case '\\':
+			if l.i+1 >= len(l.src) {
+				return token{}, errorf(l.pos(), "unterminated string")
+			}
+
+			escape := l.src[l.i+1]
+			switch escape {
+			case '"', '\\', '/':
+				b.WriteByte(escape)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				var r rune
+				if l.i+6 > len(l.src) {
+					return token{}, errorf(l.pos(), "invalid unicode escape")
+				}
+				if _, err := fmt.Sscanf(l.src[l.i+2:l.i+6], "%04x", &r); err != nil {
+					return token{}, errorf(l.pos(), "invalid unicode escape %s", l.src[l.i:l.i+6])
+				}
+				b.WriteRune(r)
+				l.i += 4
+			default:
+				return token{}, errorf(l.pos(), "invalid escape \\%c", escape)
+			}
+			l.i += 2
+		default:
+			b.WriteByte(c)
+			l.i++
+		}
+	}
+
+	return token{}, errorf(l.pos(), "unterminated string")
+}
+
+func (l *lexer) blockString(pos Position) (token, error) {
+	l.i += 3
+
+	var b strings.Builder
+	for l.i < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[l.i:], `"""`):
+			l.i += 3
+			return token{kind: tokenBlockString, value: blockStringValue(b.String()), pos: pos}, nil
+		case strings.HasPrefix(l.src[l.i:], `\"""`):
+			b.WriteString(`"""`)
+			l.i += 4
+		case l.src[l.i] == '\n':
+			b.WriteByte('\n')
+			l.i++
+			l.newline(l.i)
+		case l.src[l.i] == '\r':
+			b.WriteByte('\n')
+			l.i++
+			if l.i < len(l.src) && l.src[l.i] == '\n' {
+				l.i++
+			}
+			l.newline(l.i)
+		default:
+			b.WriteByte(l.src[l.i])
+			l.i++
+		}
+	}
+
+	return token{}, errorf(l.pos(), "unterminated block string")
+}
+
+// blockStringValue removes the common indentation and the leading and trailing blank lines,
+// as specified by https://spec.graphql.org/October2021/#BlockStringValue()
+func blockStringValue(raw string) string {
+	lines := strings.Split(raw, "\n")
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < len(line) && (commonIndent == -1 || indent < commonIndent) {
+			commonIndent = indent
+		}
+	}
+
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= commonIndent {
+				lines[i] = lines[i][commonIndent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,89 @@
+package sdl
+
+import "golang.org/x/exp/slices"
+
+// The operation types that start an operation definition
+var operationTypes = []string{"query", "mutation", "subscription"}
+
+// Operation is an operation definition of an executable document
+type Operation struct {
+	// The operation type, eg. "query", empty for the query shorthand "{ ... }"
+	Type string
+	// The name of the operation, empty if it is anonymous
+	Name string
+	// The byte offset of the document where the name of an anonymous operation belongs, just after the operation type.
+	// For the query shorthand it is the offset of the opening brace
+	NameOffset int
+}
+
+// Operations lists the operation definitions of an executable document, skipping its fragment definitions.
+// Only the structure of the definitions is checked, their selections and variables are not validated
+func Operations(body string) ([]Operation, error) {
+	lex := newLexer(Source{Body: body})
+
+	var ops []Operation
+	for {
+		tok, err := lex.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case tok.kind == tokenEOF:
+			return ops, nil
+		case tok.kind == tokenPunct && tok.value == "{":
+			ops = append(ops, Operation{NameOffset: lex.i - 1})
+		case tok.kind == tokenName && slices.Contains(operationTypes, tok.value):
+			op := Operation{Type: tok.value, NameOffset: lex.i}
+
+			if tok, err = lex.next(); err != nil {
+				return nil, err
+			}
+
+			if tok.kind == tokenName {
+				op.Name = tok.value
+				if tok, err = lex.next(); err != nil {
+					return nil, err
+				}
+			}
+
+			ops = append(ops, op)
+		case tok.kind == tokenName && tok.value == "fragment":
+		default:
+			return nil, errorf(tok.pos, "expected definition, found %s", tok)
+		}
+
+		if err := skipDefinition(lex, tok); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipDefinition consumes the rest of a definition, starting at the token, up to the end of its selection set.
+// Braces within variable definitions and arguments, eg. of object values, are part of the parentheses around them
+func skipDefinition(lex *lexer, tok token) error {
+	depth := 0
+	for {
+		switch {
+		case tok.kind == tokenEOF:
+			return errorf(tok.pos, "expected selection set, found %s", tok)
+		case tok.kind != tokenPunct:
+		case tok.value == "{" || tok.value == "(" || tok.value == "[":
+			depth++
+		case tok.value == "}" || tok.value == ")" || tok.value == "]":
+			depth--
+			if depth < 0 {
+				return errorf(tok.pos, "unexpected %s", tok)
+			}
+
+			if depth == 0 && tok.value == "}" {
+				return nil
+			}
+		}
+
+		var err error
+		if tok, err = lex.next(); err != nil {
+			return err
+		}
+	}
+}
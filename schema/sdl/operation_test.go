@@ -0,0 +1,80 @@
+package sdl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Operations(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []Operation
+		wantErr string
+	}{
+		{
+			name: "shorthand",
+			src:  "{ me { id } }",
+			want: []Operation{{NameOffset: 0}},
+		},
+		{
+			name: "anonymous with directive",
+			src:  "query @cached { me { id } }",
+			want: []Operation{{Type: "query", NameOffset: 5}},
+		},
+		{
+			name: "named",
+			src:  "mutation Delete($id: ID!) { deleteUser(id: $id) }",
+			want: []Operation{{Type: "mutation", Name: "Delete", NameOffset: 8}},
+		},
+		{
+			name: "fragments",
+			src:  "fragment F on User { id }\nquery { me { ...F } }\nfragment G on User { name }",
+			want: []Operation{{Type: "query", NameOffset: 31}},
+		},
+		{
+			name: "braces in arguments",
+			src:  "query($f: Filter = {a: [{b: 1}]}) { users(filter: {c: \"}\"}) { id } }\nsubscription S { a }",
+			want: []Operation{{Type: "query", NameOffset: 5}, {Type: "subscription", Name: "S", NameOffset: 81}},
+		},
+		{
+			name: "only fragments",
+			src:  "fragment F on User { id }",
+		},
+		{
+			name:    "unknown definition",
+			src:     "type Query { a: Int }",
+			wantErr: `1:1: expected definition, found name "type"`,
+		},
+		{
+			name:    "unterminated",
+			src:     "query { me { id }",
+			wantErr: "1:18: expected selection set, found end of input",
+		},
+		{
+			name:    "unbalanced",
+			src:     "query { me } }",
+			wantErr: `1:14: expected definition, found punctuator "}"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Operations(tt.src)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Operations(), got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Operations(), got error %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Operations(), got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
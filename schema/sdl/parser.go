@@ -0,0 +1,924 @@
+// Package sdl parses graphql schema definition language into the schema model returned by introspection
+package sdl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"golang.org/x/exp/slices"
+)
+
+type parser struct {
+	lex *lexer
+	tok token
+
+	// The defined types by name, in the order they were defined
+	types map[string]*schema.Type
+	order []string
+
+	// The type extensions, applied once every type is defined
+	extensions []extension
+
+	// The defined directives by name, in the order they were defined
+	directiveDefs  map[string]*schema.Directive
+	directiveOrder []string
+
+	// The schema definition, if any
+	schemaDef *schemaDefinition
+
+	// The named types referenced by fields and arguments, resolved once every type is defined
+	refs []typeRef
+}
+
+// refUse is where a type is referenced, which decides the kinds of types allowed
+type refUse int
+
+const (
+	// The type of a field
+	outputRef refUse = iota
+	// The type of an argument or input field
+	inputRef
+	// An interface implemented by a type
+	interfaceRef
+	// A member of a union
+	memberRef
+	// A root operation type of the schema definition
+	rootRef
+)
+
+type typeRef struct {
+	t   *schema.Type
+	use refUse
+	pos Position
+}
+
+type extension struct {
+	t   *schema.Type
+	pos Position
+}
+
+type schemaDefinition struct {
+	description string
+	// The names of the root operation types keyed by operation, eg. "query"
+	roots map[string]string
+	pos   Position
+}
+
+// Source is a named SDL document
+type Source struct {
+	// The name used in errors, eg. the file name
+	Name string
+	Body string
+}
+
+// Parse parses an SDL document into a schema
+func Parse(src string) (*schema.Schema, error) {
+	return ParseSources(Source{Body: src})
+}
+
+// ParseSources parses SDL documents that together make up a schema, eg. one file per domain
+func ParseSources(sources ...Source) (*schema.Schema, error) {
+	p := &parser{
+		types:         make(map[string]*schema.Type),
+		directiveDefs: make(map[string]*schema.Directive),
+	}
+
+	for _, src := range sources {
+		p.lex = newLexer(src)
+
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		for p.tok.kind != tokenEOF {
+			if err := p.parseDefinition(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return p.build()
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+// peek reports whether the current token is the punctuator or keyword
+func (p *parser) peek(value string) bool {
+	return (p.tok.kind == tokenPunct || p.tok.kind == tokenName) && p.tok.value == value
+}
+
+// skip consumes the current token if it is the punctuator or keyword, reporting whether it did
+func (p *parser) skip(value string) (bool, error) {
+	if !p.peek(value) {
+		return false, nil
+	}
+
+	return true, p.advance()
+}
+
+func (p *parser) expect(value string) error {
+	if !p.peek(value) {
+		return p.unexpected(fmt.Sprintf("%q", value))
+	}
+
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected("name")
+	}
+
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) unexpected(expected string) error {
+	return errorf(p.tok.pos, "expected %s, found %s", expected, p.tok)
+}
+
+// description parses the optional description preceding a definition
+func (p *parser) description() (string, error) {
+	if p.tok.kind != tokenString && p.tok.kind != tokenBlockString {
+		return "", nil
+	}
+
+	description := p.tok.value
+	return description, p.advance()
+}
+
+func (p *parser) parseDefinition() error {
+	description, err := p.description()
+	if err != nil {
+		return err
+	}
+
+	if p.tok.kind != tokenName {
+		return p.unexpected("definition")
+	}
+
+	pos := p.tok.pos
+
+	switch p.tok.value {
+	case "schema":
+		return p.parseSchema(description, pos, false)
+	case "directive":
+		return p.parseDirectiveDefinition(description)
+	case "extend":
+		return p.parseExtension()
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := p.types[t.Name]; ok {
+		return errorf(pos, "type %s is defined more than once", t.Name)
+	}
+	if strings.HasPrefix(t.Name, "__") {
+		return errorf(pos, "type %s uses a name reserved for introspection", t.Name)
+	}
+
+	t.Description = description
+	p.types[t.Name] = t
+	p.order = append(p.order, t.Name)
+
+	return nil
+}
+
+// parseType parses the type definition starting at the current keyword
+func (p *parser) parseType() (*schema.Type, error) {
+	switch keyword := p.tok.value; keyword {
+	case "type":
+		return p.parseObject(schema.ObjectTypeKind)
+	case "interface":
+		return p.parseObject(schema.InterfaceTypeKind)
+	case "union":
+		return p.parseUnion()
+	case "enum":
+		return p.parseEnum()
+	case "input":
+		return p.parseInput()
+	case "scalar":
+		return p.parseScalar()
+	default:
+		return nil, errorf(p.tok.pos, "unknown definition %s", keyword)
+	}
+}
+
+// parseExtension parses an extension of a type or the schema, which is applied once every type is defined
+func (p *parser) parseExtension() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	if p.tok.kind != tokenName {
+		return p.unexpected("type or schema extension")
+	}
+
+	pos := p.tok.pos
+	if p.tok.value == "schema" {
+		return p.parseSchema("", pos, true)
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return err
+	}
+
+	p.extensions = append(p.extensions, extension{t: t, pos: pos})
+
+	return nil
+}
+
+// parseSchema parses a schema definition or extension, eg. "schema { query: RootQuery }"
+func (p *parser) parseSchema(description string, pos Position, isExtension bool) error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	if !isExtension && p.schemaDef != nil && p.schemaDef.pos != (Position{}) {
+		return errorf(pos, "schema is defined more than once, first at %s", p.schemaDef.pos)
+	}
+	if p.schemaDef == nil {
+		p.schemaDef = &schemaDefinition{roots: make(map[string]string)}
+	}
+	if !isExtension {
+		p.schemaDef.description = description
+		p.schemaDef.pos = pos
+	}
+
+	if _, err := p.directives(); err != nil {
+		return err
+	}
+
+	if isExtension && !p.peek("{") {
+		return nil
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	for !p.peek("}") {
+		opPos := p.tok.pos
+
+		op, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		switch op {
+		case "query", "mutation", "subscription":
+		default:
+			return errorf(opPos, "unknown root operation %s", op)
+		}
+
+		if _, ok := p.schemaDef.roots[op]; ok {
+			return errorf(opPos, "root operation %s is defined more than once", op)
+		}
+
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+
+		ref, err := p.namedRef(rootRef)
+		if err != nil {
+			return err
+		}
+
+		p.schemaDef.roots[op] = ref.Name
+	}
+
+	return p.advance()
+}
+
+// parseDirectiveDefinition parses a definition like "directive @auth(role: Role!) repeatable on FIELD_DEFINITION | OBJECT"
+func (p *parser) parseDirectiveDefinition(description string) error {
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := p.directiveDefs[name]; ok {
+		return errorf(pos, "directive @%s is defined more than once", name)
+	}
+
+	d := &schema.Directive{
+		Name:        name,
+		Description: description,
+		Args:        []schema.InputValue{},
+		Locations:   []schema.DirectiveLocation{},
+	}
+
+	d.Args, err = p.arguments()
+	if err != nil {
+		return err
+	}
+
+	d.IsRepeatable, err = p.skip("repeatable")
+	if err != nil {
+		return err
+	}
+
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+
+	if _, err := p.skip("|"); err != nil {
+		return err
+	}
+
+	for {
+		locPos := p.tok.pos
+
+		loc, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(directiveLocations, schema.DirectiveLocation(loc)) {
+			return errorf(locPos, "unknown directive location %s", loc)
+		}
+		d.Locations = append(d.Locations, schema.DirectiveLocation(loc))
+
+		if ok, err := p.skip("|"); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+	}
+
+	p.directiveDefs[name] = d
+	p.directiveOrder = append(p.directiveOrder, name)
+
+	return nil
+}
+
+// parseObject parses an object or interface definition
+func (p *parser) parseObject(kind schema.TypeKind) (*schema.Type, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &schema.Type{Kind: kind, Name: name, Interfaces: []schema.Type{}}
+
+	if ok, err := p.skip("implements"); err != nil {
+		return nil, err
+	} else if ok {
+		if _, err := p.skip("&"); err != nil {
+			return nil, err
+		}
+
+		for {
+			ref, err := p.namedRef(interfaceRef)
+			if err != nil {
+				return nil, err
+			}
+			t.Interfaces = append(t.Interfaces, *ref)
+
+			if ok, err := p.skip("&"); err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+		}
+	}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	t.Fields, err = p.fields()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (p *parser) fields() ([]schema.Field, error) {
+	fields := []schema.Field{}
+	if !p.peek("{") {
+		return fields, nil
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for !p.peek("}") {
+		pos := p.tok.pos
+
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(fields, func(other schema.Field) bool { return other.Name == f.Name }) {
+			return nil, errorf(pos, "field %s is defined more than once", f.Name)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, p.advance()
+}
+
+// arguments parses the optional argument definitions of a field or directive
+func (p *parser) arguments() ([]schema.InputValue, error) {
+	args := []schema.InputValue{}
+	if !p.peek("(") {
+		return args, nil
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for !p.peek(")") {
+		pos := p.tok.pos
+
+		arg, err := p.inputValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(args, func(other schema.InputValue) bool { return other.Name == arg.Name }) {
+			return nil, errorf(pos, "argument %s is defined more than once", arg.Name)
+		}
+
+		args = append(args, arg)
+	}
+
+	return args, p.advance()
+}
+
+func (p *parser) field() (schema.Field, error) {
+	description, err := p.description()
+	if err != nil {
+		return schema.Field{}, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return schema.Field{}, err
+	}
+
+	f := schema.Field{Name: name, Description: description}
+
+	f.Args, err = p.arguments()
+	if err != nil {
+		return schema.Field{}, err
+	}
+
+	if err := p.expect(":"); err != nil {
+		return schema.Field{}, err
+	}
+
+	f.Type, err = p.typeRef(outputRef)
+	if err != nil {
+		return schema.Field{}, err
+	}
+
+	ds, err := p.directives()
+	if err != nil {
+		return schema.Field{}, err
+	}
+	f.IsDeprecated, f.DeprecationReason = ds.deprecation()
+
+	return f, nil
+}
+
+// inputValue parses an argument or input field definition
+func (p *parser) inputValue() (schema.InputValue, error) {
+	description, err := p.description()
+	if err != nil {
+		return schema.InputValue{}, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return schema.InputValue{}, err
+	}
+
+	if err := p.expect(":"); err != nil {
+		return schema.InputValue{}, err
+	}
+
+	t, err := p.typeRef(inputRef)
+	if err != nil {
+		return schema.InputValue{}, err
+	}
+
+	v := schema.InputValue{Name: name, Description: description, Type: t}
+
+	// Introspection returns default values as they would be written in graphql
+	if ok, err := p.skip("="); err != nil {
+		return schema.InputValue{}, err
+	} else if ok {
+		v.DefaultValue, err = p.value()
+		if err != nil {
+			return schema.InputValue{}, err
+		}
+	}
+
+	if _, err := p.directives(); err != nil {
+		return schema.InputValue{}, err
+	}
+
+	return v, nil
+}
+
+// typeRef parses a reference to a type, eg. "[String!]!", into the wrapping types introspection returns
+func (p *parser) typeRef(use refUse) (*schema.Type, error) {
+	var t *schema.Type
+
+	if p.peek("[") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		ofType, err := p.typeRef(use)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+
+		t = &schema.Type{Kind: schema.ListTypeKind, OfType: ofType}
+	} else {
+		var err error
+		t, err = p.namedRef(use)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("!") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		t = &schema.Type{Kind: schema.NonNullTypeKind, OfType: t}
+	}
+
+	return t, nil
+}
+
+// namedRef parses the name of a type, leaving its kind to be resolved once every type is defined
+func (p *parser) namedRef(use refUse) (*schema.Type, error) {
+	pos := p.tok.pos
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &schema.Type{Name: name}
+	p.refs = append(p.refs, typeRef{t: t, use: use, pos: pos})
+
+	return t, nil
+}
+
+func (p *parser) parseUnion() (*schema.Type, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &schema.Type{Kind: schema.UnionTypeKind, Name: name, PossibleTypes: []schema.Type{}}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skip("="); err != nil || !ok {
+		return t, err
+	}
+
+	if _, err := p.skip("|"); err != nil {
+		return nil, err
+	}
+
+	for {
+		ref, err := p.namedRef(memberRef)
+		if err != nil {
+			return nil, err
+		}
+		t.PossibleTypes = append(t.PossibleTypes, *ref)
+
+		if ok, err := p.skip("|"); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+	}
+
+	return t, nil
+}
+
+func (p *parser) parseEnum() (*schema.Type, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &schema.Type{Kind: schema.EnumTypeKind, Name: name, EnumValues: []schema.EnumValue{}}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	if !p.peek("{") {
+		return t, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for !p.peek("}") {
+		description, err := p.description()
+		if err != nil {
+			return nil, err
+		}
+
+		pos := p.tok.pos
+
+		value, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		switch value {
+		case "true", "false", "null":
+			return nil, errorf(pos, "enum value %s is reserved", value)
+		}
+		if slices.ContainsFunc(t.EnumValues, func(other schema.EnumValue) bool { return other.Name == value }) {
+			return nil, errorf(pos, "enum value %s is defined more than once", value)
+		}
+
+		ds, err := p.directives()
+		if err != nil {
+			return nil, err
+		}
+
+		v := schema.EnumValue{Name: value, Description: description}
+		v.IsDeprecated, v.DeprecationReason = ds.deprecation()
+
+		t.EnumValues = append(t.EnumValues, v)
+	}
+
+	return t, p.advance()
+}
+
+func (p *parser) parseInput() (*schema.Type, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &schema.Type{Kind: schema.InputObjectTypeKind, Name: name, InputFields: []schema.InputValue{}}
+
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	if !p.peek("{") {
+		return t, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for !p.peek("}") {
+		pos := p.tok.pos
+
+		v, err := p.inputValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(t.InputFields, func(other schema.InputValue) bool { return other.Name == v.Name }) {
+			return nil, errorf(pos, "input field %s is defined more than once", v.Name)
+		}
+
+		t.InputFields = append(t.InputFields, v)
+	}
+
+	return t, p.advance()
+}
+
+func (p *parser) parseScalar() (*schema.Type, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &schema.Type{Kind: schema.ScalarTypeKind, Name: name}
+
+	ds, err := p.directives()
+	if err != nil {
+		return nil, err
+	}
+	t.SpecifiedByURL = ds.argument("specifiedBy", "url")
+
+	return t, nil
+}
+
+// appliedDirective is a directive used on a definition, with its arguments as literal values
+type appliedDirective struct {
+	name string
+	args map[string]string
+}
+
+type appliedDirectives []appliedDirective
+
+func (ds appliedDirectives) argument(directive string, arg string) string {
+	for _, d := range ds {
+		if d.name == directive {
+			return unquote(d.args[arg])
+		}
+	}
+
+	return ""
+}
+
+// deprecation returns whether the @deprecated directive is applied and the reason it gives
+func (ds appliedDirectives) deprecation() (bool, string) {
+	for _, d := range ds {
+		if d.name != "deprecated" {
+			continue
+		}
+
+		if reason, ok := d.args["reason"]; ok {
+			return true, unquote(reason)
+		}
+
+		// The default reason, as specified by https://spec.graphql.org/October2021/#sec--deprecated
+		return true, "No longer supported"
+	}
+
+	return false, ""
+}
+
+func (p *parser) directives() (appliedDirectives, error) {
+	var ds appliedDirectives
+
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+
+		d := appliedDirective{name: name, args: make(map[string]string)}
+
+		if p.peek("(") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			for !p.peek(")") {
+				arg, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+
+				d.args[arg], err = p.value()
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+// value parses a constant value, returning it as written in graphql, eg. "\"text\"", "[1, 2]" or "{a: B}"
+func (p *parser) value() (string, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenInt, tokenFloat, tokenName:
+		return tok.value, p.advance()
+	case tokenString, tokenBlockString:
+		return quote(tok.value), p.advance()
+	}
+
+	switch {
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+
+		var values []string
+		for !p.peek("]") {
+			v, err := p.value()
+			if err != nil {
+				return "", err
+			}
+
+			values = append(values, v)
+		}
+
+		return "[" + strings.Join(values, ", ") + "]", p.advance()
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+
+		var fields []string
+		for !p.peek("}") {
+			name, err := p.expectName()
+			if err != nil {
+				return "", err
+			}
+
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+
+			v, err := p.value()
+			if err != nil {
+				return "", err
+			}
+
+			fields = append(fields, name+": "+v)
+		}
+
+		return "{" + strings.Join(fields, ", ") + "}", p.advance()
+	}
+
+	return "", p.unexpected("value")
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+// unquote returns the string of a quoted literal value, or the value as is if it is not a string
+func unquote(v string) string {
+	var s string
+	if _, err := fmt.Sscanf(v, "%q", &s); err != nil {
+		return v
+	}
+
+	return s
+}
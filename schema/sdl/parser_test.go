@@ -0,0 +1,315 @@
+package sdl
+
+import (
+	"testing"
+
+	"github.com/TheLeeeo/gql-test-suite/schema"
+)
+
+const testSchema = `
+"""
+A user of the service
+"""
+type User implements Node & Named {
+  id: ID!
+  name: String
+  posts(first: Int!, tags: [String!]): [Post!]! @deprecated(reason: "use feed")
+}
+
+interface Node {
+  id: ID!
+}
+
+interface Named {
+  name: String
+}
+
+type Post implements Node {
+  id: ID!
+}
+
+union SearchResult = | User | Post
+
+enum Role {
+  ADMIN
+  "A regular user"
+  USER @deprecated
+}
+
+input UserFilter {
+  role: Role!
+}
+
+scalar DateTime @specifiedBy(url: "https://example.com/date-time")
+
+type Query {
+  user(id: ID!): User
+  search(filter: UserFilter): [SearchResult]
+}
+`
+
+func Test_Parse(t *testing.T) {
+	s, err := Parse(testSchema)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if s.QueryType == nil || s.QueryType.Name != "Query" {
+		t.Errorf("QueryType = %v, want Query", s.QueryType)
+	}
+	if s.MutationType != nil {
+		t.Errorf("MutationType = %v, want nil", s.MutationType)
+	}
+
+	user := s.GetType("User")
+	if user == nil {
+		t.Fatal("User type not found")
+	}
+	if user.Kind != schema.ObjectTypeKind || user.Description != "A user of the service" {
+		t.Errorf("User = %s %q, want OBJECT with description", user.Kind, user.Description)
+	}
+	if len(user.Interfaces) != 2 || user.Interfaces[0].Name != "Node" || user.Interfaces[0].Kind != schema.InterfaceTypeKind {
+		t.Errorf("User.Interfaces = %v, want Node and Named", user.Interfaces)
+	}
+
+	posts := user.Fields[2]
+	if !posts.IsDeprecated || posts.DeprecationReason != "use feed" {
+		t.Errorf("posts deprecation = %v %q, want deprecated for \"use feed\"", posts.IsDeprecated, posts.DeprecationReason)
+	}
+	if got := posts.Type.OfType.OfType.OfType; got.Name != "Post" || got.Kind != schema.ObjectTypeKind {
+		t.Errorf("posts type = %v, want [Post!]!", posts.Type)
+	}
+	if got := posts.Args[1].Type.OfType.OfType; got.Name != "String" || got.Kind != schema.ScalarTypeKind {
+		t.Errorf("tags type = %v, want [String!]", posts.Args[1].Type)
+	}
+
+	node := s.GetType("Node")
+	if len(node.PossibleTypes) != 2 {
+		t.Errorf("Node.PossibleTypes = %v, want User and Post", node.PossibleTypes)
+	}
+
+	union := s.GetType("SearchResult")
+	if union.Kind != schema.UnionTypeKind || len(union.PossibleTypes) != 2 || union.PossibleTypes[1].Kind != schema.ObjectTypeKind {
+		t.Errorf("SearchResult = %v, want union of User and Post", union)
+	}
+
+	role := s.GetType("Role")
+	if len(role.EnumValues) != 2 || !role.EnumValues[1].IsDeprecated || role.EnumValues[1].Description != "A regular user" {
+		t.Errorf("Role.EnumValues = %v, want ADMIN and deprecated USER", role.EnumValues)
+	}
+
+	if dt := s.GetType("DateTime"); dt.SpecifiedByURL != "https://example.com/date-time" {
+		t.Errorf("DateTime.SpecifiedByURL = %q", dt.SpecifiedByURL)
+	}
+
+	if s.GetType("Boolean") == nil {
+		t.Error("built in scalar Boolean not found")
+	}
+}
+
+const testSchemaDefinition = `
+"The service"
+schema @link(url: "https://specs.apollo.dev/federation/v2.0") {
+  query: RootQuery
+}
+
+extend schema {
+  mutation: RootMutation
+}
+
+"Restricts a field to a role"
+directive @auth(role: Role = ADMIN, scopes: [String!] = ["read", "write"]) repeatable on FIELD_DEFINITION | OBJECT
+
+type RootQuery {
+  users(first: Int = 10, filter: Filter = {name: "a\\b", active: true}): [User] @auth
+}
+
+type RootMutation {
+  noop: Boolean
+}
+
+type User {
+  id: ID!
+}
+
+extend type User implements Node {
+  name: String
+}
+
+interface Node {
+  id: ID!
+}
+
+input Filter {
+  name: String
+  active: Boolean = false
+}
+
+enum Role { ADMIN }
+
+extend enum Role { USER }
+`
+
+func Test_ParseSchemaDefinition(t *testing.T) {
+	s, err := Parse(testSchemaDefinition)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if s.Description != "The service" {
+		t.Errorf("Description = %q, want \"The service\"", s.Description)
+	}
+	if s.QueryType == nil || s.QueryType.Name != "RootQuery" {
+		t.Errorf("QueryType = %v, want RootQuery", s.QueryType)
+	}
+	if s.MutationType == nil || s.MutationType.Name != "RootMutation" {
+		t.Errorf("MutationType = %v, want RootMutation", s.MutationType)
+	}
+
+	var auth *schema.Directive
+	for i, d := range s.Directives {
+		if d.Name == "auth" {
+			auth = &s.Directives[i]
+		}
+	}
+	if auth == nil {
+		t.Fatal("directive @auth not found")
+	}
+	if !auth.IsRepeatable || len(auth.Locations) != 2 || auth.Description != "Restricts a field to a role" {
+		t.Errorf("@auth = %+v", auth)
+	}
+	if auth.Args[0].DefaultValue != "ADMIN" || auth.Args[1].DefaultValue != `["read", "write"]` {
+		t.Errorf("@auth default values = %q, %q", auth.Args[0].DefaultValue, auth.Args[1].DefaultValue)
+	}
+	if len(s.Directives) != 5 {
+		t.Errorf("len(Directives) = %d, want the 4 built in directives and @auth", len(s.Directives))
+	}
+
+	users := s.GetType("RootQuery").Fields[0]
+	if users.Args[0].DefaultValue != "10" || users.Args[1].DefaultValue != `{name: "a\\b", active: true}` {
+		t.Errorf("users default values = %q, %q", users.Args[0].DefaultValue, users.Args[1].DefaultValue)
+	}
+
+	user := s.GetType("User")
+	if len(user.Fields) != 2 || len(user.Interfaces) != 1 || user.Interfaces[0].Kind != schema.InterfaceTypeKind {
+		t.Errorf("extended User = %+v", user)
+	}
+	if node := s.GetType("Node"); len(node.PossibleTypes) != 1 {
+		t.Errorf("Node.PossibleTypes = %v, want User", node.PossibleTypes)
+	}
+	if role := s.GetType("Role"); len(role.EnumValues) != 2 {
+		t.Errorf("Role.EnumValues = %v, want ADMIN and USER", role.EnumValues)
+	}
+}
+
+func Test_ParseSources(t *testing.T) {
+	s, err := ParseSources(
+		Source{Name: "a.graphql", Body: "extend type Query { b: B }"},
+		Source{Name: "b.graphql", Body: "type Query { a: String }\ntype B { id: ID }"},
+	)
+	if err != nil {
+		t.Fatalf("ParseSources() error = %v", err)
+	}
+
+	if q := s.GetType("Query"); len(q.Fields) != 2 {
+		t.Errorf("Query.Fields = %v, want a and b", q.Fields)
+	}
+
+	_, err = ParseSources(Source{Name: "a.graphql", Body: "type Query {\n  a: Missing\n}"})
+	if err == nil || err.Error() != "a.graphql:2:6: unknown type Missing" {
+		t.Errorf("ParseSources() error = %v, want a.graphql:2:6: unknown type Missing", err)
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "unknown type",
+			src:  "type Query {\n  user: User\n}",
+			want: "2:9: unknown type User",
+		},
+		{
+			name: "missing type",
+			src:  "type Query {\n  user\n}",
+			want: `3:1: expected ":", found punctuator "}"`,
+		},
+		{
+			name: "duplicate type",
+			src:  "scalar A\nscalar A",
+			want: "2:1: type A is defined more than once",
+		},
+		{
+			name: "input type as field type",
+			src:  "input A { a: Int }\ntype Query { a: A }",
+			want: "2:17: input type A cannot be the type of a field",
+		},
+		{
+			name: "object type as argument",
+			src:  "type Query { a(b: Query): Int }",
+			want: "1:19: OBJECT type Query cannot be the type of an argument or input field",
+		},
+		{
+			name: "implementing an object",
+			src:  "type A { a: Int }\ntype Query implements A { a: Int }",
+			want: "2:23: OBJECT type A cannot be implemented, it is not an interface",
+		},
+		{
+			name: "extending an unknown type",
+			src:  "type Query { a: Int }\nextend type B { b: Int }",
+			want: "2:8: cannot extend unknown type B",
+		},
+		{
+			name: "extending a type as another kind",
+			src:  "type Query { a: Int }\nextend input Query { b: Int }",
+			want: "2:8: cannot extend OBJECT Query as INPUT_OBJECT",
+		},
+		{
+			name: "duplicate field in extension",
+			src:  "type Query { a: Int }\nextend type Query { a: Int }",
+			want: "2:8: field Query.a is defined more than once",
+		},
+		{
+			name: "duplicate field",
+			src:  "type Query {\n  a: Int\n  a: String\n}",
+			want: "3:3: field a is defined more than once",
+		},
+		{
+			name: "schema without query",
+			src:  "type M { a: Int }\nschema { mutation: M }",
+			want: "2:1: schema has no query root operation type",
+		},
+		{
+			name: "unknown root operation",
+			src:  "type Query { a: Int }\nschema { fragment: Query }",
+			want: "2:10: unknown root operation fragment",
+		},
+		{
+			name: "unknown directive location",
+			src:  "directive @a on FIELD | TYPE",
+			want: "1:25: unknown directive location TYPE",
+		},
+		{
+			name: "invalid default value",
+			src:  "type Query { a(b: Int = ): Int }",
+			want: `1:25: expected value, found punctuator ")"`,
+		},
+		{
+			name: "unterminated string",
+			src:  `"description`,
+			want: "1:13: unterminated string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
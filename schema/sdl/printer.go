@@ -0,0 +1,284 @@
+package sdl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/TheLeeeo/gql-test-suite/schema"
+	"golang.org/x/exp/slices"
+)
+
+// The reason of @deprecated when none is given
+const defaultDeprecationReason = "No longer supported"
+
+// Print writes the schema as SDL. Types, directives, fields and values are sorted by name so that
+// the output only changes when the schema does. Built in scalars, directives and introspection types are left out
+func Print(w io.Writer, s *schema.Schema) error {
+	var blocks []string
+
+	if def := printSchemaDefinition(s); def != "" {
+		blocks = append(blocks, def)
+	}
+
+	directives := slices.Clone(s.Directives)
+	slices.SortFunc(directives, func(a, b schema.Directive) int { return strings.Compare(a.Name, b.Name) })
+	for _, d := range directives {
+		if isBuiltinDirective(d.Name) {
+			continue
+		}
+
+		blocks = append(blocks, printDirective(d))
+	}
+
+	types := slices.Clone(s.Types)
+	slices.SortFunc(types, func(a, b schema.Type) int { return strings.Compare(a.Name, b.Name) })
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") || t.Kind == schema.ScalarTypeKind && slices.Contains(builtinScalars, t.Name) {
+			continue
+		}
+
+		blocks = append(blocks, printType(t))
+	}
+
+	_, err := io.WriteString(w, strings.Join(blocks, "\n\n")+"\n")
+	return err
+}
+
+func isBuiltinDirective(name string) bool {
+	for _, d := range builtinDirectives() {
+		if d.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// printSchemaDefinition prints the schema definition, which is left out if the root operation types have their default names
+func printSchemaDefinition(s *schema.Schema) string {
+	roots := []struct {
+		op          string
+		t           *schema.Type
+		defaultName string
+	}{
+		{"query", s.QueryType, "Query"},
+		{"mutation", s.MutationType, "Mutation"},
+		{"subscription", s.SubscriptionType, "Subscription"},
+	}
+
+	isDefault := s.Description == ""
+	for _, r := range roots {
+		if r.t != nil && r.t.Name != r.defaultName {
+			isDefault = false
+		}
+	}
+	if isDefault {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(printDescription(s.Description, ""))
+	b.WriteString("schema {\n")
+	for _, r := range roots {
+		if r.t != nil {
+			fmt.Fprintf(&b, "  %s: %s\n", r.op, r.t.Name)
+		}
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func printDirective(d schema.Directive) string {
+	var b strings.Builder
+	b.WriteString(printDescription(d.Description, ""))
+	fmt.Fprintf(&b, "directive @%s%s", d.Name, printArgs(d.Args, ""))
+	if d.IsRepeatable {
+		b.WriteString(" repeatable")
+	}
+
+	locations := make([]string, len(d.Locations))
+	for i, l := range d.Locations {
+		locations[i] = string(l)
+	}
+	fmt.Fprintf(&b, " on %s", strings.Join(locations, " | "))
+
+	return b.String()
+}
+
+func printType(t schema.Type) string {
+	var b strings.Builder
+	b.WriteString(printDescription(t.Description, ""))
+
+	switch t.Kind {
+	case schema.ScalarTypeKind:
+		fmt.Fprintf(&b, "scalar %s", t.Name)
+		if t.SpecifiedByURL != "" {
+			fmt.Fprintf(&b, " @specifiedBy(url: %s)", quote(t.SpecifiedByURL))
+		}
+	case schema.ObjectTypeKind, schema.InterfaceTypeKind:
+		keyword := "type"
+		if t.Kind == schema.InterfaceTypeKind {
+			keyword = "interface"
+		}
+
+		fmt.Fprintf(&b, "%s %s", keyword, t.Name)
+		if len(t.Interfaces) > 0 {
+			fmt.Fprintf(&b, " implements %s", strings.Join(sortedNames(t.Interfaces), " & "))
+		}
+		b.WriteString(printFields(t.Fields))
+	case schema.UnionTypeKind:
+		fmt.Fprintf(&b, "union %s", t.Name)
+		if len(t.PossibleTypes) > 0 {
+			fmt.Fprintf(&b, " = %s", strings.Join(sortedNames(t.PossibleTypes), " | "))
+		}
+	case schema.EnumTypeKind:
+		fmt.Fprintf(&b, "enum %s", t.Name)
+		b.WriteString(printEnumValues(t.EnumValues))
+	case schema.InputObjectTypeKind:
+		fmt.Fprintf(&b, "input %s", t.Name)
+		b.WriteString(printInputFields(t.InputFields))
+	}
+
+	return b.String()
+}
+
+func sortedNames(types []schema.Type) []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.Name
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func printFields(fields []schema.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	fields = slices.Clone(fields)
+	slices.SortFunc(fields, func(a, b schema.Field) int { return strings.Compare(a.Name, b.Name) })
+
+	var b strings.Builder
+	b.WriteString(" {\n")
+	for _, f := range fields {
+		b.WriteString(printDescription(f.Description, "  "))
+		fmt.Fprintf(&b, "  %s%s: %s%s\n", f.Name, printArgs(f.Args, "  "), f.Type.String(), printDeprecation(f.IsDeprecated, f.DeprecationReason))
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// printArgs prints the arguments on one line, or one per line if any of them has a description
+func printArgs(args []schema.InputValue, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	multiline := slices.ContainsFunc(args, func(a schema.InputValue) bool { return a.Description != "" })
+
+	printed := make([]string, len(args))
+	for i, a := range args {
+		if multiline {
+			printed[i] = printDescription(a.Description, indent+"  ") + indent + "  " + printInputValue(a)
+		} else {
+			printed[i] = printInputValue(a)
+		}
+	}
+
+	if multiline {
+		return "(\n" + strings.Join(printed, "\n") + "\n" + indent + ")"
+	}
+
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func printInputValue(v schema.InputValue) string {
+	s := fmt.Sprintf("%s: %s", v.Name, v.Type.String())
+	if v.DefaultValue != "" {
+		s += " = " + v.DefaultValue
+	}
+
+	return s
+}
+
+func printInputFields(fields []schema.InputValue) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	fields = slices.Clone(fields)
+	slices.SortFunc(fields, func(a, b schema.InputValue) int { return strings.Compare(a.Name, b.Name) })
+
+	var b strings.Builder
+	b.WriteString(" {\n")
+	for _, f := range fields {
+		b.WriteString(printDescription(f.Description, "  "))
+		fmt.Fprintf(&b, "  %s\n", printInputValue(f))
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func printEnumValues(values []schema.EnumValue) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	values = slices.Clone(values)
+	slices.SortFunc(values, func(a, b schema.EnumValue) int { return strings.Compare(a.Name, b.Name) })
+
+	var b strings.Builder
+	b.WriteString(" {\n")
+	for _, v := range values {
+		b.WriteString(printDescription(v.Description, "  "))
+		fmt.Fprintf(&b, "  %s%s\n", v.Name, printDeprecation(v.IsDeprecated, v.DeprecationReason))
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func printDeprecation(deprecated bool, reason string) string {
+	if !deprecated {
+		return ""
+	}
+
+	if reason == "" || reason == defaultDeprecationReason {
+		return " @deprecated"
+	}
+
+	return fmt.Sprintf(" @deprecated(reason: %s)", quote(reason))
+}
+
+// printDescription prints the description on the line before a definition,
+// as a block string if it spans several lines
+func printDescription(description string, indent string) string {
+	if description == "" {
+		return ""
+	}
+
+	if !strings.ContainsAny(description, "\n\"\\") {
+		return indent + quote(description) + "\n"
+	}
+
+	lines := strings.Split(strings.ReplaceAll(description, `"""`, `\"""`), "\n")
+
+	var b strings.Builder
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range lines {
+		if line == "" {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(indent + line + "\n")
+		}
+	}
+	b.WriteString(indent + `"""` + "\n")
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package sdl
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Print(t *testing.T) {
+	src := `
+schema { query: Root }
+
+type Root {
+  "Multi\nline"
+  users(first: Int = 10, "The filter" filter: Filter): [User!]!
+  me: User @deprecated
+}
+
+type User implements Node { id: ID! }
+
+interface Node { id: ID! }
+
+input Filter { name: String = "a" }
+`
+
+	want := `schema {
+  query: Root
+}
+
+input Filter {
+  name: String = "a"
+}
+
+interface Node {
+  id: ID!
+}
+
+type Root {
+  me: User @deprecated
+  """
+  Multi
+  line
+  """
+  users(
+    first: Int = 10
+    "The filter"
+    filter: Filter
+  ): [User!]!
+}
+
+type User implements Node {
+  id: ID!
+}
+`
+
+	s, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var b strings.Builder
+	if err := Print(&b, s); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	if got := b.String(); got != want {
+		t.Errorf("Print() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_PrintRoundTrip(t *testing.T) {
+	for name, src := range map[string]string{"schema": testSchema, "schema definition": testSchemaDefinition} {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var first strings.Builder
+			if err := Print(&first, s); err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+
+			reparsed, err := Parse(first.String())
+			if err != nil {
+				t.Fatalf("Parse() of printed schema error = %v\n%s", err, first.String())
+			}
+
+			var second strings.Builder
+			if err := Print(&second, reparsed); err != nil {
+				t.Fatalf("Print() error = %v", err)
+			}
+
+			if first.String() != second.String() {
+				t.Errorf("printing the printed schema changed it, first:\n%s\nsecond:\n%s", first.String(), second.String())
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 package schema
 
-import "fmt"
+import "github.com/TheLeeeo/gql-test-suite/schema/scalars"
 
 type Type struct {
 	Kind           TypeKind     `json:"kind"`
@@ -36,17 +36,20 @@ func (t *Type) GetBaseType() *Type {
 	return t.OfType.GetBaseType()
 }
 
-// Builds a string representation of the type, eg. "String", "String!", "[String]!", "[String!]!"
-func buildArgTypeString(t *Type) string {
-	if t.Kind == NonNullTypeKind {
-		return fmt.Sprintf("%s!", buildArgTypeString(t.OfType))
+// String returns the type as it is written in operations and SDL, eg. "String", "String!", "[String]!", "[String!]!"
+func (t *Type) String() string {
+	if t == nil {
+		return ""
 	}
 
-	if t.Kind == ListTypeKind {
-		return fmt.Sprintf("[%s]", buildArgTypeString(t.OfType))
+	switch t.Kind {
+	case NonNullTypeKind:
+		return t.OfType.String() + "!"
+	case ListTypeKind:
+		return "[" + t.OfType.String() + "]"
+	default:
+		return t.Name
 	}
-
-	return string(t.Name)
 }
 
 func (t *Type) GetDefaultValue() any {
@@ -59,21 +62,9 @@ func (t *Type) GetDefaultValue() any {
 	}
 
 	if t.Kind == ScalarTypeKind {
-		if t.Name == "Boolean" {
-			return true
-		} else if t.Name == "String" {
-			return "0"
-		} else if t.Name == "Int" {
-			return 0
-		} else if t.Name == "Float" {
-			return 0.0
-		} else if t.Name == "ID" {
-			return "0"
-		} else if t.Name == "Time" {
-			return "2019-01-01T00:00:00Z"
-		} else {
-			panic(fmt.Sprintf("Unhandled scalar type %s", t.Name))
-		}
+		// Unknown scalars have no default value
+		v, _ := scalars.Generate(t.Name, t.SpecifiedByURL)
+		return v
 	}
 
 	return nil
@@ -101,3 +101,47 @@ func Test_GetBaseType(t *testing.T) {
 		})
 	}
 }
+
+func Test_TypeString(t *testing.T) {
+	str := &Type{Kind: ScalarTypeKind, Name: "String"}
+
+	tests := []struct {
+		name string
+		t    *Type
+		want string
+	}{
+		{
+			name: "Named",
+			t:    str,
+			want: "String",
+		},
+		{
+			name: "NonNull",
+			t:    &Type{Kind: NonNullTypeKind, OfType: str},
+			want: "String!",
+		},
+		{
+			name: "List",
+			t:    &Type{Kind: ListTypeKind, OfType: str},
+			want: "[String]",
+		},
+		{
+			name: "NonNullListOfNonNull",
+			t:    &Type{Kind: NonNullTypeKind, OfType: &Type{Kind: ListTypeKind, OfType: &Type{Kind: NonNullTypeKind, OfType: str}}},
+			want: "[String!]!",
+		},
+		{
+			name: "Nil",
+			t:    nil,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
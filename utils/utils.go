@@ -52,3 +52,19 @@ func LoadParams(file string) map[string]any {
 
 	return input
 }
+
+// ParseHeaders parses headers formatted like "name:value" into a map of the names to the values
+func ParseHeaders(headers []string) (map[string]string, error) {
+	headerMap := make(map[string]string)
+
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header: %s", header)
+		}
+
+		headerMap[name] = value
+	}
+
+	return headerMap, nil
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_ParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			headers: nil,
+			want:    map[string]string{},
+		},
+		{
+			name:    "several",
+			headers: []string{"Authorization:Bearer token", "X-Tenant:acme"},
+			want:    map[string]string{"Authorization": "Bearer token", "X-Tenant": "acme"},
+		},
+		{
+			name:    "value with colons",
+			headers: []string{"Origin:https://example.com"},
+			want:    map[string]string{"Origin": "https://example.com"},
+		},
+		{
+			name:    "no value",
+			headers: []string{"Authorization"},
+			wantErr: true,
+		},
+		{
+			name:    "no name",
+			headers: []string{":token"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHeaders(tt.headers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHeaders(), got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHeaders(), got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}